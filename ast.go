@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"gomonkey/ast"
+	"gomonkey/lexer"
+	"gomonkey/parser"
+	"io"
+	"os"
+)
+
+func runAst(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("ast", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "print the syntax tree as JSON (same as -format=json)")
+	format := flags.String("format", "text", "output format: text, json, dot or mermaid")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() != 1 {
+		fmt.Fprintf(stderr, "usage: gomonkey ast [-json] [-format text|json|dot|mermaid] file\n")
+		return 2
+	}
+
+	if *asJSON {
+		if *format != "text" && *format != "json" {
+			fmt.Fprintf(stderr, "ast: -json conflicts with -format=%s\n", *format)
+			return 2
+		}
+		*format = "json"
+	}
+
+	switch *format {
+	case "text", "json", "dot", "mermaid":
+	default:
+		fmt.Fprintf(stderr, "ast: unknown format %q\n", *format)
+		return 2
+	}
+
+	file := flags.Arg(0)
+	source, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintf(stderr, "ast: %s\n", err)
+		return 2
+	}
+
+	parser := parser.New(lexer.New(string(source)))
+	program := parser.ParseProgram()
+	if len(parser.PositionedErrors()) > 0 {
+		for _, err := range parser.PositionedErrors() {
+			fmt.Fprintf(stderr, "%s:%s\n", file, err)
+		}
+		return 1
+	}
+
+	switch *format {
+	case "text":
+		fmt.Fprintln(stdout, program.String())
+		return 0
+	case "dot":
+		fmt.Fprint(stdout, ast.DOT(program))
+		return 0
+	case "mermaid":
+		fmt.Fprint(stdout, ast.Mermaid(program))
+		return 0
+	}
+
+	data, err := ast.MarshalJSON(program)
+	if err != nil {
+		fmt.Fprintf(stderr, "ast: %s\n", err)
+		return 2
+	}
+
+	var out bytes.Buffer
+	json.Indent(&out, data, "", "  ")
+	out.WriteString("\n")
+	out.WriteTo(stdout)
+	return 0
+}
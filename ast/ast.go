@@ -3,6 +3,8 @@ package ast
 import (
 	"bytes"
 	"gomonkey/token"
+	"math/big"
+	"strconv"
 	"strings"
 )
 
@@ -44,9 +46,12 @@ func (program *Program) String() string {
 }
 
 type LetStatement struct {
-	Token token.Token
-	Name  *Identifier
-	Value Expression
+	Token   token.Token
+	Name    *Identifier
+	Pattern Pattern
+	Type    TypeExpression
+	Value   Expression
+	End     token.Token
 }
 
 func (ls *LetStatement) statementNode() {}
@@ -58,7 +63,14 @@ func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(ls.TokenLiteral() + " ")
-	out.WriteString(ls.Name.String())
+	if ls.Pattern != nil {
+		out.WriteString(ls.Pattern.String())
+	} else {
+		out.WriteString(ls.Name.String())
+	}
+	if ls.Type != nil {
+		out.WriteString(": " + ls.Type.String())
+	}
 	out.WriteString(" = ")
 
 	if ls.Value != nil {
@@ -72,6 +84,7 @@ func (ls *LetStatement) String() string {
 type Identifier struct {
 	Token token.Token
 	Value string
+	Type  TypeExpression
 }
 
 func (ident *Identifier) expressionNode() {}
@@ -80,12 +93,16 @@ func (ident *Identifier) TokenLiteral() string {
 }
 
 func (ident *Identifier) String() string {
+	if ident.Type != nil {
+		return ident.Value + ": " + ident.Type.String()
+	}
 	return ident.Value
 }
 
 type ReturnStatement struct {
 	Token       token.Token
 	ReturnValue Expression
+	End         token.Token
 }
 
 func (rs *ReturnStatement) statementNode() {}
@@ -109,6 +126,7 @@ func (rs *ReturnStatement) String() string {
 type ExpressionStatement struct {
 	Token      token.Token
 	Expression Expression
+	End        token.Token
 }
 
 func (es *ExpressionStatement) statementNode() {}
@@ -126,6 +144,7 @@ func (es *ExpressionStatement) String() string {
 type IntegerLiteral struct {
 	Token token.Token
 	Value int64
+	Big   *big.Int
 }
 
 func (il *IntegerLiteral) expressionNode() {}
@@ -136,6 +155,19 @@ func (il *IntegerLiteral) String() string {
 	return il.Token.Literal
 }
 
+type StringLiteral struct {
+	Token token.Token
+	Value string
+}
+
+func (sl *StringLiteral) expressionNode() {}
+func (sl *StringLiteral) TokenLiteral() string {
+	return sl.Token.Literal
+}
+func (sl *StringLiteral) String() string {
+	return strconv.Quote(sl.Value)
+}
+
 type PrefixExpression struct {
 	Token    token.Token
 	Operator string
@@ -180,6 +212,29 @@ func (oe *InfixExpression) String() string {
 	return out.String()
 }
 
+type AssignExpression struct {
+	Token    token.Token
+	Target   Expression
+	Operator string
+	Value    Expression
+}
+
+func (ae *AssignExpression) expressionNode() {}
+func (ae *AssignExpression) TokenLiteral() string {
+	return ae.Token.Literal
+}
+func (ae *AssignExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ae.Target.String())
+	out.WriteString(" " + ae.Operator + " ")
+	out.WriteString(ae.Value.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
 type Boolean struct {
 	Token token.Token
 	Value bool
@@ -193,6 +248,23 @@ func (b *Boolean) String() string {
 	return b.Token.Literal
 }
 
+type GroupedExpression struct {
+	Token      token.Token
+	Expression Expression
+	End        token.Token
+}
+
+func (ge *GroupedExpression) expressionNode() {}
+func (ge *GroupedExpression) TokenLiteral() string {
+	return ge.Token.Literal
+}
+func (ge *GroupedExpression) String() string {
+	if ge.Expression != nil {
+		return ge.Expression.String()
+	}
+	return ""
+}
+
 type IfExpression struct {
 	Token       token.Token
 	Condition   Expression
@@ -220,9 +292,35 @@ func (ie *IfExpression) String() string {
 	return out.String()
 }
 
+type ConditionalExpression struct {
+	Token       token.Token
+	Condition   Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+func (ce *ConditionalExpression) expressionNode() {}
+func (ce *ConditionalExpression) TokenLiteral() string {
+	return ce.Token.Literal
+}
+func (ce *ConditionalExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ce.Condition.String())
+	out.WriteString(" ? ")
+	out.WriteString(ce.Consequence.String())
+	out.WriteString(" : ")
+	out.WriteString(ce.Alternative.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
 type BlockStatement struct {
 	Token      token.Token
 	Statements []Statement
+	End        token.Token
 }
 
 func (bs *BlockStatement) statementNode() {}
@@ -239,7 +337,9 @@ func (bs *BlockStatement) String() string {
 
 type FunctionLiteral struct {
 	Token      token.Token
-	Parameters []*Identifier
+	Name       string
+	Parameters []Pattern
+	ReturnType TypeExpression
 	Body       *BlockStatement
 }
 
@@ -259,7 +359,377 @@ func (fl *FunctionLiteral) String() string {
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
 	out.WriteString(") ")
+	if fl.ReturnType != nil {
+		out.WriteString("-> " + fl.ReturnType.String() + " ")
+	}
 	out.WriteString(fl.Body.String())
 
 	return out.String()
 }
+
+type WhileStatement struct {
+	Token     token.Token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (ws *WhileStatement) statementNode() {}
+func (ws *WhileStatement) TokenLiteral() string {
+	return ws.Token.Literal
+}
+func (ws *WhileStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("while")
+	out.WriteString(ws.Condition.String())
+	out.WriteString(" ")
+	out.WriteString(ws.Body.String())
+
+	return out.String()
+}
+
+type ForStatement struct {
+	Token     token.Token
+	Init      Statement
+	Condition Expression
+	Update    Expression
+	Body      *BlockStatement
+}
+
+func (fs *ForStatement) statementNode() {}
+func (fs *ForStatement) TokenLiteral() string {
+	return fs.Token.Literal
+}
+func (fs *ForStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for (")
+	if fs.Init != nil {
+		out.WriteString(strings.TrimSuffix(fs.Init.String(), ";"))
+	}
+	out.WriteString("; ")
+	if fs.Condition != nil {
+		out.WriteString(fs.Condition.String())
+	}
+	out.WriteString("; ")
+	if fs.Update != nil {
+		out.WriteString(fs.Update.String())
+	}
+	out.WriteString(") ")
+	out.WriteString(fs.Body.String())
+
+	return out.String()
+}
+
+type ForInStatement struct {
+	Token    token.Token
+	Variable *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fis *ForInStatement) statementNode() {}
+func (fis *ForInStatement) TokenLiteral() string {
+	return fis.Token.Literal
+}
+func (fis *ForInStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for (")
+	out.WriteString(fis.Variable.String())
+	out.WriteString(" in ")
+	out.WriteString(fis.Iterable.String())
+	out.WriteString(") ")
+	out.WriteString(fis.Body.String())
+
+	return out.String()
+}
+
+type BreakStatement struct {
+	Token token.Token
+	End   token.Token
+}
+
+func (bs *BreakStatement) statementNode() {}
+func (bs *BreakStatement) TokenLiteral() string {
+	return bs.Token.Literal
+}
+func (bs *BreakStatement) String() string {
+	return bs.Token.Literal + ";"
+}
+
+type ContinueStatement struct {
+	Token token.Token
+	End   token.Token
+}
+
+func (cs *ContinueStatement) statementNode() {}
+func (cs *ContinueStatement) TokenLiteral() string {
+	return cs.Token.Literal
+}
+func (cs *ContinueStatement) String() string {
+	return cs.Token.Literal + ";"
+}
+
+type CallExpression struct {
+	Token     token.Token
+	Function  Expression
+	Arguments []Expression
+	End       token.Token
+}
+
+func (ce *CallExpression) expressionNode() {}
+func (ce *CallExpression) TokenLiteral() string {
+	return ce.Token.Literal
+}
+func (ce *CallExpression) String() string {
+	var out bytes.Buffer
+
+	args := []string{}
+	for _, a := range ce.Arguments {
+		args = append(args, a.String())
+	}
+
+	out.WriteString(ce.Function.String())
+	out.WriteString("(")
+	out.WriteString(strings.Join(args, ", "))
+	out.WriteString(")")
+
+	return out.String()
+}
+
+type MemberExpression struct {
+	Token    token.Token
+	Object   Expression
+	Property *Identifier
+}
+
+func (me *MemberExpression) expressionNode() {}
+func (me *MemberExpression) TokenLiteral() string {
+	return me.Token.Literal
+}
+func (me *MemberExpression) String() string {
+	return me.Object.String() + "." + me.Property.String()
+}
+
+type IndexExpression struct {
+	Token token.Token
+	Left  Expression
+	Index Expression
+	End   token.Token
+}
+
+func (ie *IndexExpression) expressionNode() {}
+func (ie *IndexExpression) TokenLiteral() string {
+	return ie.Token.Literal
+}
+func (ie *IndexExpression) String() string {
+	return "(" + ie.Left.String() + "[" + ie.Index.String() + "])"
+}
+
+type SpreadExpression struct {
+	Token token.Token
+	Value Expression
+}
+
+func (se *SpreadExpression) expressionNode() {}
+func (se *SpreadExpression) TokenLiteral() string {
+	return se.Token.Literal
+}
+func (se *SpreadExpression) String() string {
+	return se.Token.Literal + se.Value.String()
+}
+
+type NamedArgument struct {
+	Token token.Token
+	Name  *Identifier
+	Value Expression
+}
+
+func (na *NamedArgument) expressionNode() {}
+func (na *NamedArgument) TokenLiteral() string {
+	return na.Token.Literal
+}
+func (na *NamedArgument) String() string {
+	return na.Name.String() + ": " + na.Value.String()
+}
+
+type MatchExpression struct {
+	Token   token.Token
+	Subject Expression
+	Arms    []*MatchArm
+	End     token.Token
+}
+
+func (me *MatchExpression) expressionNode() {}
+func (me *MatchExpression) TokenLiteral() string {
+	return me.Token.Literal
+}
+func (me *MatchExpression) String() string {
+	var out bytes.Buffer
+
+	arms := []string{}
+	for _, arm := range me.Arms {
+		arms = append(arms, arm.String())
+	}
+
+	out.WriteString("match (")
+	out.WriteString(me.Subject.String())
+	out.WriteString(") { ")
+	out.WriteString(strings.Join(arms, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+type MatchArm struct {
+	Token   token.Token
+	Pattern Pattern
+	Guard   Expression
+	Body    Expression
+}
+
+func (ma *MatchArm) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ma.Pattern.String())
+	if ma.Guard != nil {
+		out.WriteString(" if ")
+		out.WriteString(ma.Guard.String())
+	}
+	out.WriteString(" => ")
+	out.WriteString(ma.Body.String())
+
+	return out.String()
+}
+
+type ThrowStatement struct {
+	Token token.Token
+	Value Expression
+	End   token.Token
+}
+
+func (ts *ThrowStatement) statementNode() {}
+func (ts *ThrowStatement) TokenLiteral() string {
+	return ts.Token.Literal
+}
+func (ts *ThrowStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ts.TokenLiteral() + " ")
+	if ts.Value != nil {
+		out.WriteString(ts.Value.String())
+	}
+	out.WriteString(";")
+
+	return out.String()
+}
+
+type TryExpression struct {
+	Token          token.Token
+	Block          *BlockStatement
+	CatchParameter *Identifier
+	Catch          *BlockStatement
+	Finally        *BlockStatement
+}
+
+func (te *TryExpression) expressionNode() {}
+func (te *TryExpression) TokenLiteral() string {
+	return te.Token.Literal
+}
+func (te *TryExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("try ")
+	out.WriteString(te.Block.String())
+
+	if te.Catch != nil {
+		out.WriteString(" catch (")
+		out.WriteString(te.CatchParameter.String())
+		out.WriteString(") ")
+		out.WriteString(te.Catch.String())
+	}
+
+	if te.Finally != nil {
+		out.WriteString(" finally ")
+		out.WriteString(te.Finally.String())
+	}
+
+	return out.String()
+}
+
+type ImportStatement struct {
+	Token token.Token
+	Path  *StringLiteral
+	Alias *Identifier
+	End   token.Token
+}
+
+func (is *ImportStatement) statementNode() {}
+func (is *ImportStatement) TokenLiteral() string {
+	return is.Token.Literal
+}
+func (is *ImportStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(is.TokenLiteral() + " ")
+	out.WriteString(is.Path.String())
+	out.WriteString(" as ")
+	out.WriteString(is.Alias.String())
+	out.WriteString(";")
+
+	return out.String()
+}
+
+type ExportStatement struct {
+	Token     token.Token
+	Statement *LetStatement
+}
+
+func (es *ExportStatement) statementNode() {}
+func (es *ExportStatement) TokenLiteral() string {
+	return es.Token.Literal
+}
+func (es *ExportStatement) String() string {
+	return es.TokenLiteral() + " " + es.Statement.String()
+}
+
+func StatementToken(statement Statement) token.Token {
+	switch statement := statement.(type) {
+	case *LetStatement:
+		return statement.Token
+	case *ReturnStatement:
+		return statement.Token
+	case *ExpressionStatement:
+		return statement.Token
+	case *BlockStatement:
+		return statement.Token
+	case *WhileStatement:
+		return statement.Token
+	case *ForStatement:
+		return statement.Token
+	case *ForInStatement:
+		return statement.Token
+	case *BreakStatement:
+		return statement.Token
+	case *ContinueStatement:
+		return statement.Token
+	case *ThrowStatement:
+		return statement.Token
+	case *ImportStatement:
+		return statement.Token
+	case *ExportStatement:
+		return statement.Token
+	}
+	return token.Token{}
+}
+
+func Unparen(expression Expression) Expression {
+	for {
+		grouped, ok := expression.(*GroupedExpression)
+		if !ok {
+			return expression
+		}
+		expression = grouped.Expression
+	}
+}
@@ -2,6 +2,8 @@ package ast
 
 import (
 	"gomonkey/token"
+	"math/big"
+	"strings"
 	"testing"
 )
 
@@ -26,3 +28,169 @@ func TestString(t *testing.T) {
 		t.Errorf("program.String() wrong got=%q", program.String())
 	}
 }
+
+func TestInspect(t *testing.T) {
+	program := &Program{
+		Statements: []Statement{
+			&ExpressionStatement{
+				Token: token.Token{Type: token.IF, Literal: "if"},
+				Expression: &IfExpression{
+					Token:     token.Token{Type: token.IF, Literal: "if"},
+					Condition: &Identifier{Token: token.Token{Type: token.IDENT, Literal: "x"}, Value: "x"},
+					Consequence: &BlockStatement{
+						Statements: []Statement{
+							&ExpressionStatement{
+								Expression: &Identifier{Token: token.Token{Type: token.IDENT, Literal: "y"}, Value: "y"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	identifiers := []string{}
+	Inspect(program, func(node Node) bool {
+		if ident, ok := node.(*Identifier); ok {
+			identifiers = append(identifiers, ident.Value)
+		}
+		return true
+	})
+
+	if strings.Join(identifiers, ",") != "x,y" {
+		t.Errorf("Inspect visited wrong identifiers. got=%v", identifiers)
+	}
+
+	visited := 0
+	Inspect(program, func(node Node) bool {
+		visited++
+		_, isIf := node.(*IfExpression)
+		return !isIf
+	})
+
+	if visited != 3 {
+		t.Errorf("Inspect did not stop at IfExpression. visited=%d", visited)
+	}
+}
+
+func TestBoundNames(t *testing.T) {
+	ident := func(name string) *Identifier {
+		return &Identifier{Token: token.Token{Type: token.IDENT, Literal: name}, Value: name}
+	}
+
+	pattern := &ArrayPattern{
+		Elements: []Pattern{
+			ident("a"),
+			&HashPattern{Pairs: []*HashPatternPair{{Key: ident("key"), Value: ident("b")}}},
+			&DefaultPattern{Target: ident("c"), Default: ident("fallback")},
+			&WildcardPattern{},
+			&RestPattern{Name: ident("rest")},
+		},
+	}
+
+	names := []string{}
+	for _, name := range BoundNames(pattern) {
+		names = append(names, name.Value)
+	}
+
+	if strings.Join(names, ",") != "a,b,c,rest" {
+		t.Errorf("BoundNames returned wrong names. got=%v", names)
+	}
+}
+
+func graphProgram() *Program {
+	return &Program{
+		Statements: []Statement{
+			&ExpressionStatement{
+				Expression: &InfixExpression{
+					Token:    token.Token{Type: token.LT, Literal: "<"},
+					Left:     &Identifier{Token: token.Token{Type: token.IDENT, Literal: "x"}, Value: "x"},
+					Operator: "<",
+					Right:    &StringLiteral{Token: token.Token{Type: token.STRING, Literal: "a\"b"}, Value: "a\"b"},
+				},
+			},
+		},
+	}
+}
+
+func TestDOT(t *testing.T) {
+	expected := `digraph AST {
+  node [shape=box];
+  n0 [label="Program"];
+  n1 [label="ExpressionStatement"];
+  n2 [label="InfixExpression <"];
+  n3 [label="Identifier x"];
+  n4 [label="StringLiteral \"a\\\"b\""];
+  n0 -> n1 [label="Statements[0]"];
+  n1 -> n2 [label="Expression"];
+  n2 -> n3 [label="Left"];
+  n2 -> n4 [label="Right"];
+}
+`
+
+	if dot := DOT(graphProgram()); dot != expected {
+		t.Errorf("DOT wrong.\nexpected:\n%s\ngot:\n%s", expected, dot)
+	}
+}
+
+func TestMermaid(t *testing.T) {
+	expected := `flowchart TD
+  n0["Program"]
+  n1["ExpressionStatement"]
+  n2["InfixExpression #lt;"]
+  n3["Identifier x"]
+  n4["StringLiteral #quot;a\#quot;b#quot;"]
+  n0 -->|"Statements[0]"| n1
+  n1 -->|"Expression"| n2
+  n2 -->|"Left"| n3
+  n2 -->|"Right"| n4
+`
+
+	if mermaid := Mermaid(graphProgram()); mermaid != expected {
+		t.Errorf("Mermaid wrong.\nexpected:\n%s\ngot:\n%s", expected, mermaid)
+	}
+}
+
+func TestGraphLabels(t *testing.T) {
+	tests := []struct {
+		node     Node
+		expected string
+	}{
+		{&IntegerLiteral{Value: 42}, "IntegerLiteral 42"},
+		{&IntegerLiteral{Big: new(big.Int).Lsh(big.NewInt(1), 64)}, "IntegerLiteral 18446744073709551616"},
+		{&Boolean{Value: true}, "Boolean true"},
+		{&PrefixExpression{Operator: "!"}, "PrefixExpression !"},
+		{&AssignExpression{Operator: "+="}, "AssignExpression +="},
+		{&FunctionLiteral{Name: "add"}, "FunctionLiteral add"},
+		{&FunctionLiteral{}, "FunctionLiteral"},
+		{&NamedType{Name: "int"}, "NamedType int"},
+		{&BlockStatement{}, "BlockStatement"},
+	}
+
+	for _, tt := range tests {
+		if label := graphLabel(tt.node); label != tt.expected {
+			t.Errorf("graphLabel wrong. expected=%q, got=%q", tt.expected, label)
+		}
+	}
+}
+
+func TestStatementToken(t *testing.T) {
+	let := token.Token{Type: token.LET, Literal: "let", Line: 2, Column: 3}
+	brk := token.Token{Type: token.BREAK, Literal: "break", Line: 4, Column: 1}
+
+	tests := []struct {
+		statement Statement
+		expected  token.Token
+	}{
+		{&LetStatement{Token: let}, let},
+		{&ExportStatement{Token: let}, let},
+		{&BreakStatement{Token: brk}, brk},
+		{nil, token.Token{}},
+	}
+
+	for _, tt := range tests {
+		if tok := StatementToken(tt.statement); tok != tt.expected {
+			t.Errorf("StatementToken(%T) wrong. expected=%+v, got=%+v", tt.statement, tt.expected, tok)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package ast
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+func Equal(a, b Node) bool {
+	return Diff(a, b) == ""
+}
+
+func Diff(a, b Node) string {
+	return diff("", reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem())
+}
+
+func diff(path string, a, b reflect.Value) string {
+	if a.Kind() == reflect.Interface {
+		a, b = a.Elem(), b.Elem()
+	}
+
+	if isNil(a) || isNil(b) {
+		if isNil(a) && isNil(b) {
+			return ""
+		}
+		return mismatch(path, describe(a), describe(b))
+	}
+
+	if a.Type() != b.Type() {
+		return mismatch(path, describe(a), describe(b))
+	}
+
+	switch {
+	case a.Type() == bigIntType:
+		if a.Interface().(*big.Int).Cmp(b.Interface().(*big.Int)) != 0 {
+			return mismatch(path, a.Interface().(*big.Int).String(), b.Interface().(*big.Int).String())
+		}
+		return ""
+	case a.Type() == tokenType:
+		if d := diff(join(path, "Type"), a.FieldByName("Type"), b.FieldByName("Type")); d != "" {
+			return d
+		}
+		return diff(join(path, "Literal"), a.FieldByName("Literal"), b.FieldByName("Literal"))
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return diff(path, a.Elem(), b.Elem())
+	case reflect.Struct:
+		for _, tokens := range []bool{false, true} {
+			for i := 0; i < a.NumField(); i++ {
+				if (a.Field(i).Type() == tokenType) != tokens || a.Type().Field(i).Name == "End" {
+					continue
+				}
+				if d := diff(join(path, a.Type().Field(i).Name), a.Field(i), b.Field(i)); d != "" {
+					return d
+				}
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < min(a.Len(), b.Len()); i++ {
+			if d := diff(fmt.Sprintf("%s[%d]", path, i), a.Index(i), b.Index(i)); d != "" {
+				return d
+			}
+		}
+		if a.Len() != b.Len() {
+			return mismatch(path, fmt.Sprintf("len %d", a.Len()), fmt.Sprintf("len %d", b.Len()))
+		}
+	case reflect.String:
+		if a.String() != b.String() {
+			return mismatch(path, fmt.Sprintf("%q", a.String()), fmt.Sprintf("%q", b.String()))
+		}
+	default:
+		if a.Interface() != b.Interface() {
+			return mismatch(path, fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+		}
+	}
+
+	return ""
+}
+
+func isNil(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+func describe(v reflect.Value) string {
+	if isNil(v) {
+		return "nil"
+	}
+	if v.Kind() == reflect.Pointer {
+		return v.Elem().Type().Name()
+	}
+	return v.Type().Name()
+}
+
+func join(path string, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func mismatch(path string, a string, b string) string {
+	if path == "" {
+		return a + " != " + b
+	}
+	return path + ": " + a + " != " + b
+}
@@ -0,0 +1,65 @@
+package ast_test
+
+import (
+	"gomonkey/ast"
+	"gomonkey/lexer"
+	"gomonkey/parser"
+	"testing"
+)
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) > 0 {
+		t.Fatalf("parser errors for %q: %v", input, p.Errors())
+	}
+	return program
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected bool
+	}{
+		{"let x = 1 + 2;", "let x = 1 + 2;", true},
+		{"let x = 1 + 2;", "let   x =\n  1 +   2", true},
+		{"if (x) { y } else { z }", "if (x) { y } else { w }", false},
+		{"if (x) { y }", "if (x) { y } else { }", false},
+		{"f(a, b)", "f(a)", false},
+		{"(a)", "a", false},
+		{"99999999999999999999", "99999999999999999999", true},
+		{"99999999999999999999", "99999999999999999998", false},
+	}
+
+	for _, tt := range tests {
+		if equal := ast.Equal(parse(t, tt.a), parse(t, tt.b)); equal != tt.expected {
+			t.Errorf("Equal(%q, %q) wrong. expected=%t, got=%t", tt.a, tt.b, tt.expected, equal)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected string
+	}{
+		{"let x = 1 + 2;", "let x = 1 + 2;", ""},
+		{"1; 2; x + y;", "1; 2; x - y;", `Statements[2].Expression.Operator: "+" != "-"`},
+		{"if (x) { y } else { z }", "if (x) { y } else { w }", `Statements[0].Expression.Alternative.Statements[0].Expression.Value: "z" != "w"`},
+		{"if (x) { y }", "if (x) { y } else { }", "Statements[0].Expression.Alternative: nil != BlockStatement"},
+		{"f(a, b)", "f(a)", "Statements[0].Expression.Arguments: len 2 != len 1"},
+		{"-x", "!x", `Statements[0].Expression.Operator: "-" != "!"`},
+		{"x", "1", "Statements[0].Expression: Identifier != IntegerLiteral"},
+		{"(a) + b", "a + b", "Statements[0].Expression.Left: GroupedExpression != Identifier"},
+		{"let y = x;", "let y = 1;", "Statements[0].Value: Identifier != IntegerLiteral"},
+		{"true", "false", "Statements[0].Expression.Value: true != false"},
+	}
+
+	for _, tt := range tests {
+		if diff := ast.Diff(parse(t, tt.a), parse(t, tt.b)); diff != tt.expected {
+			t.Errorf("Diff(%q, %q) wrong.\nexpected=%s\ngot=%s", tt.a, tt.b, tt.expected, diff)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type graph struct {
+	nodes []graphNode
+	edges []graphEdge
+}
+
+type graphNode struct {
+	id    string
+	label string
+}
+
+type graphEdge struct {
+	from  string
+	to    string
+	label string
+}
+
+func DOT(node Node) string {
+	g := buildGraph(node)
+
+	var out bytes.Buffer
+	out.WriteString("digraph AST {\n")
+	out.WriteString("  node [shape=box];\n")
+	for _, n := range g.nodes {
+		fmt.Fprintf(&out, "  %s [label=%s];\n", n.id, dotQuote(n.label))
+	}
+	for _, e := range g.edges {
+		fmt.Fprintf(&out, "  %s -> %s [label=%s];\n", e.from, e.to, dotQuote(e.label))
+	}
+	out.WriteString("}\n")
+
+	return out.String()
+}
+
+func Mermaid(node Node) string {
+	g := buildGraph(node)
+
+	var out bytes.Buffer
+	out.WriteString("flowchart TD\n")
+	for _, n := range g.nodes {
+		fmt.Fprintf(&out, "  %s[%s]\n", n.id, mermaidQuote(n.label))
+	}
+	for _, e := range g.edges {
+		fmt.Fprintf(&out, "  %s -->|%s| %s\n", e.from, mermaidQuote(e.label), e.to)
+	}
+
+	return out.String()
+}
+
+func buildGraph(node Node) *graph {
+	g := &graph{}
+	if node != nil {
+		g.add(reflect.ValueOf(node))
+	}
+	return g
+}
+
+func (g *graph) add(v reflect.Value) {
+	id := fmt.Sprintf("n%d", len(g.nodes))
+	g.nodes = append(g.nodes, graphNode{id: id, label: graphLabel(v.Interface())})
+
+	v = v.Elem()
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Name
+		field := v.Field(i)
+
+		if field.Kind() == reflect.Slice {
+			for j := 0; j < field.Len(); j++ {
+				g.addChild(id, fmt.Sprintf("%s[%d]", name, j), field.Index(j))
+			}
+			continue
+		}
+		g.addChild(id, name, field)
+	}
+}
+
+func (g *graph) addChild(parent string, label string, v reflect.Value) {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Type() == bigIntType {
+		return
+	}
+	if _, ok := nodeTypes[v.Elem().Type().Name()]; !ok {
+		return
+	}
+
+	g.edges = append(g.edges, graphEdge{from: parent, to: fmt.Sprintf("n%d", len(g.nodes)), label: label})
+	g.add(v)
+}
+
+func graphLabel(node interface{}) string {
+	name := reflect.TypeOf(node).Elem().Name()
+
+	switch node := node.(type) {
+	case *Identifier:
+		return name + " " + node.Value
+	case *IntegerLiteral:
+		if node.Big != nil {
+			return name + " " + node.Big.String()
+		}
+		return name + " " + strconv.FormatInt(node.Value, 10)
+	case *StringLiteral:
+		return name + " " + strconv.Quote(node.Value)
+	case *Boolean:
+		return name + " " + strconv.FormatBool(node.Value)
+	case *PrefixExpression:
+		return name + " " + node.Operator
+	case *InfixExpression:
+		return name + " " + node.Operator
+	case *AssignExpression:
+		return name + " " + node.Operator
+	case *FunctionLiteral:
+		if node.Name != "" {
+			return name + " " + node.Name
+		}
+	case *NamedType:
+		return name + " " + node.Name
+	}
+	return name
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;", "\n", " ").Replace(s) + `"`
+}
@@ -0,0 +1,296 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gomonkey/token"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+type Span struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+var nodeTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, node := range []interface{}{
+		&Program{}, &LetStatement{}, &Identifier{}, &ReturnStatement{}, &ExpressionStatement{},
+		&IntegerLiteral{}, &StringLiteral{}, &PrefixExpression{}, &InfixExpression{}, &AssignExpression{},
+		&Boolean{}, &GroupedExpression{}, &IfExpression{}, &ConditionalExpression{}, &BlockStatement{}, &FunctionLiteral{},
+		&WhileStatement{}, &ForStatement{}, &ForInStatement{}, &BreakStatement{}, &ContinueStatement{},
+		&CallExpression{}, &MemberExpression{}, &IndexExpression{}, &SpreadExpression{}, &NamedArgument{}, &MatchExpression{},
+		&MatchArm{}, &ThrowStatement{}, &TryExpression{}, &ImportStatement{}, &ExportStatement{},
+		&WildcardPattern{}, &LiteralPattern{}, &ArrayPattern{}, &HashPattern{}, &HashPatternPair{},
+		&RestPattern{}, &DefaultPattern{}, &NamedType{}, &FunctionType{},
+	} {
+		t := reflect.TypeOf(node).Elem()
+		nodeTypes[t.Name()] = t
+	}
+}
+
+var (
+	tokenType  = reflect.TypeOf(token.Token{})
+	bigIntType = reflect.TypeOf(&big.Int{})
+)
+
+type jsonToken struct {
+	Type    token.TokenType `json:"type"`
+	Literal string          `json:"literal"`
+	Line    int             `json:"line"`
+	Column  int             `json:"column"`
+}
+
+func MarshalJSON(node Node) ([]byte, error) {
+	var out bytes.Buffer
+	if err := encode(&out, reflect.ValueOf(node)); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func encode(out *bytes.Buffer, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			out.WriteString("null")
+			return nil
+		}
+		if v.Type() == bigIntType {
+			return encodeValue(out, v.Interface().(*big.Int).String())
+		}
+		return encode(out, v.Elem())
+	case reflect.Slice:
+		if v.IsNil() {
+			out.WriteString("null")
+			return nil
+		}
+		out.WriteString("[")
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				out.WriteString(",")
+			}
+			if err := encode(out, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		out.WriteString("]")
+		return nil
+	case reflect.Struct:
+		return encodeStruct(out, v)
+	case reflect.String, reflect.Bool, reflect.Int64:
+		return encodeValue(out, v.Interface())
+	}
+	return fmt.Errorf("cannot encode %s", v.Type())
+}
+
+func encodeStruct(out *bytes.Buffer, v reflect.Value) error {
+	t := v.Type()
+	if nodeTypes[t.Name()] != t {
+		return fmt.Errorf("cannot encode %s", t)
+	}
+
+	out.WriteString(`{"type":`)
+	encodeValue(out, t.Name())
+
+	if span, ok := spanOf(v); ok {
+		out.WriteString(`,"span":`)
+		encodeValue(out, span)
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		out.WriteString(",")
+		encodeValue(out, jsonKey(field))
+		out.WriteString(":")
+
+		if field.Type == tokenType {
+			tok := v.Field(i).Interface().(token.Token)
+			encodeValue(out, jsonToken{Type: tok.Type, Literal: tok.Literal, Line: tok.Line, Column: tok.Column})
+			continue
+		}
+
+		if err := encode(out, v.Field(i)); err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+	}
+
+	out.WriteString("}")
+	return nil
+}
+
+func encodeValue(out *bytes.Buffer, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	out.Write(data)
+	return nil
+}
+
+func jsonKey(field reflect.StructField) string {
+	switch {
+	case field.Name == "Token":
+		return "token"
+	case field.Name == "Type":
+		return "typeAnnotation"
+	}
+	return strings.ToLower(field.Name[:1]) + field.Name[1:]
+}
+
+func spanOf(v reflect.Value) (Span, bool) {
+	var span Span
+	found := false
+
+	var visit func(v reflect.Value)
+	visit = func(v reflect.Value) {
+		switch v.Kind() {
+		case reflect.Interface, reflect.Pointer:
+			if !v.IsNil() && v.Type() != bigIntType {
+				visit(v.Elem())
+			}
+		case reflect.Slice:
+			for i := 0; i < v.Len(); i++ {
+				visit(v.Index(i))
+			}
+		case reflect.Struct:
+			if v.Type() != tokenType {
+				for i := 0; i < v.NumField(); i++ {
+					visit(v.Field(i))
+				}
+				return
+			}
+
+			tok := v.Interface().(token.Token)
+			if tok.Line == 0 {
+				return
+			}
+
+			start := Position{Line: tok.Line, Column: tok.Column}
+			end := Position{Line: tok.Line, Column: tok.Column + len(tok.Literal)}
+			if tok.Type == token.STRING {
+				end.Column += 2
+			}
+
+			if !found || start.before(span.Start) {
+				span.Start = start
+			}
+			if !found || span.End.before(end) {
+				span.End = end
+			}
+			found = true
+		}
+	}
+
+	visit(v)
+	return span, found
+}
+
+func (p Position) before(other Position) bool {
+	return p.Line < other.Line || p.Line == other.Line && p.Column < other.Column
+}
+
+func UnmarshalProgram(data []byte) (*Program, error) {
+	node, err := decodeNode(json.RawMessage(data))
+	if err != nil {
+		return nil, err
+	}
+
+	program, ok := node.Interface().(*Program)
+	if !ok {
+		return nil, fmt.Errorf("expected Program, got %s", node.Elem().Type().Name())
+	}
+	return program, nil
+}
+
+func decodeNode(data json.RawMessage) (reflect.Value, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return reflect.Value{}, err
+	}
+
+	var name string
+	if err := json.Unmarshal(object["type"], &name); err != nil {
+		return reflect.Value{}, fmt.Errorf("missing node type")
+	}
+
+	t, ok := nodeTypes[name]
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("unknown node type %q", name)
+	}
+
+	node := reflect.New(t)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		raw, ok := object[jsonKey(field)]
+		if !ok {
+			continue
+		}
+
+		if err := decode(raw, node.Elem().Field(i)); err != nil {
+			return reflect.Value{}, fmt.Errorf("%s.%s: %w", name, field.Name, err)
+		}
+	}
+
+	return node, nil
+}
+
+func decode(data json.RawMessage, v reflect.Value) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	switch {
+	case v.Type() == tokenType:
+		var tok jsonToken
+		if err := json.Unmarshal(data, &tok); err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(token.Token{Type: tok.Type, Literal: tok.Literal, Line: tok.Line, Column: tok.Column}))
+	case v.Type() == bigIntType:
+		var digits string
+		if err := json.Unmarshal(data, &digits); err != nil {
+			return err
+		}
+		value, ok := new(big.Int).SetString(digits, 10)
+		if !ok {
+			return fmt.Errorf("invalid integer %q", digits)
+		}
+		v.Set(reflect.ValueOf(value))
+	case v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer:
+		node, err := decodeNode(data)
+		if err != nil {
+			return err
+		}
+		if !node.Type().AssignableTo(v.Type()) {
+			return fmt.Errorf("%s cannot be used as %s", node.Elem().Type().Name(), v.Type())
+		}
+		v.Set(node)
+	case v.Kind() == reflect.Slice:
+		var elements []json.RawMessage
+		if err := json.Unmarshal(data, &elements); err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(v.Type(), len(elements), len(elements))
+		for i, element := range elements {
+			if err := decode(element, slice.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		v.Set(slice)
+	default:
+		return json.Unmarshal(data, v.Addr().Interface())
+	}
+	return nil
+}
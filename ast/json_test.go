@@ -0,0 +1,173 @@
+package ast_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gomonkey/ast"
+	"gomonkey/lexer"
+	"gomonkey/parser"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const everyNode = `import "lib/math" as math;
+export let add = fn(a: int, b: int = 2, ...rest) -> int { return a + b; };
+let [first, _, ...others] = triple;
+let {x, y: [z = 1]} = point;
+let big = 99999999999999999999999;
+let f: fn(int) -> bool = fn(n) { n > 0 };
+while (!done && -x < 10) { x += 1; if (x == 5) { break; } else { continue; } }
+for (let i = 0; i < 3; i = i + 1) { print(i); }
+for (item in items) { item.name; }
+let r = match (v) { 0 => "zero", [h, ...t] if h => h, _ => true ? 1 : 2 };
+let t = try { throw "oops"; } catch (e) { e } finally { add(...args, b: 2) };
+let g = x * (a + b);
+xs[0] = h["k"];
+`
+
+func TestJSONRoundTrip(t *testing.T) {
+	p := parser.New(lexer.New(everyNode))
+	program := p.ParseProgram()
+	if len(p.Errors()) > 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+
+	data, err := ast.MarshalJSON(program)
+	if err != nil {
+		t.Fatalf("MarshalJSON error: %s", err)
+	}
+
+	for _, name := range []string{
+		"ImportStatement", "ExportStatement", "FunctionLiteral", "DefaultPattern", "RestPattern",
+		"ArrayPattern", "WildcardPattern", "HashPattern", "HashPatternPair", "NamedType", "FunctionType",
+		"WhileStatement", "AssignExpression", "IfExpression", "BreakStatement", "ContinueStatement",
+		"ForStatement", "ForInStatement", "MemberExpression", "MatchExpression", "MatchArm",
+		"LiteralPattern", "ConditionalExpression", "TryExpression", "ThrowStatement", "NamedArgument",
+		"SpreadExpression", "PrefixExpression", "InfixExpression", "CallExpression", "Boolean",
+		"StringLiteral", "IntegerLiteral", "ReturnStatement", "GroupedExpression",
+		"IndexExpression",
+	} {
+		if !bytes.Contains(data, []byte(`"type":"`+name+`"`)) {
+			t.Errorf("encoded program has no %s node", name)
+		}
+	}
+
+	decoded, err := ast.UnmarshalProgram(data)
+	if err != nil {
+		t.Fatalf("UnmarshalProgram error: %s", err)
+	}
+
+	if !reflect.DeepEqual(program, decoded) {
+		t.Errorf("decoded program differs from original.\nexpected=%s\ngot=%s", program, decoded)
+	}
+
+	again, err := ast.MarshalJSON(decoded)
+	if err != nil {
+		t.Fatalf("MarshalJSON of decoded program error: %s", err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Errorf("re-encoded program differs.\nexpected=%s\ngot=%s", data, again)
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	p := parser.New(lexer.New(`-x + "hi"`))
+	program := p.ParseProgram()
+
+	data, err := ast.MarshalJSON(program.Statements[0].(*ast.ExpressionStatement).Expression)
+	if err != nil {
+		t.Fatalf("MarshalJSON error: %s", err)
+	}
+
+	expected := `{"type":"InfixExpression","span":{"start":{"line":1,"column":1},"end":{"line":1,"column":10}},` +
+		`"token":{"type":"+","literal":"+","line":1,"column":4},` +
+		`"left":{"type":"PrefixExpression","span":{"start":{"line":1,"column":1},"end":{"line":1,"column":3}},` +
+		`"token":{"type":"-","literal":"-","line":1,"column":1},"operator":"-",` +
+		`"right":{"type":"Identifier","span":{"start":{"line":1,"column":2},"end":{"line":1,"column":3}},` +
+		`"token":{"type":"IDENT","literal":"x","line":1,"column":2},"value":"x","typeAnnotation":null}},` +
+		`"operator":"+",` +
+		`"right":{"type":"StringLiteral","span":{"start":{"line":1,"column":6},"end":{"line":1,"column":10}},` +
+		`"token":{"type":"STRING","literal":"hi","line":1,"column":6},"value":"hi"}}`
+
+	if string(data) != expected {
+		t.Errorf("wrong JSON.\nexpected=%s\ngot=%s", expected, data)
+	}
+}
+
+func TestSpansCoverDelimiters(t *testing.T) {
+	input := "f(a, b);\nlet g = fn() { 1 };\nx * (a + b);\nlet [p, {q}] = r;\nmatch (v) { _ => 0 }"
+
+	tests := []struct {
+		node     string
+		expected string
+	}{
+		{"CallExpression", "1:1-1:8"},
+		{"ExpressionStatement", "1:1-1:9"},
+		{"LetStatement", "2:1-2:20"},
+		{"FunctionLiteral", "2:9-2:19"},
+		{"BlockStatement", "2:14-2:19"},
+		{"GroupedExpression", "3:5-3:12"},
+		{"ArrayPattern", "4:5-4:13"},
+		{"HashPattern", "4:9-4:12"},
+		{"MatchExpression", "5:1-5:21"},
+	}
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) > 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+
+	spans := map[string]string{}
+	ast.Inspect(program, func(node ast.Node) bool {
+		name := reflect.TypeOf(node).Elem().Name()
+		if _, ok := spans[name]; ok {
+			return true
+		}
+
+		data, err := ast.MarshalJSON(node)
+		if err != nil {
+			t.Fatalf("MarshalJSON error: %s", err)
+		}
+
+		var decoded struct {
+			Span ast.Span `json:"span"`
+		}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("json.Unmarshal error: %s", err)
+		}
+
+		start, end := decoded.Span.Start, decoded.Span.End
+		spans[name] = fmt.Sprintf("%d:%d-%d:%d", start.Line, start.Column, end.Line, end.Column)
+		return true
+	})
+
+	for _, tt := range tests {
+		if spans[tt.node] != tt.expected {
+			t.Errorf("%s span wrong. expected=%s, got=%s", tt.node, tt.expected, spans[tt.node])
+		}
+	}
+}
+
+func TestUnmarshalProgramErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`[]`, "cannot unmarshal array"},
+		{`{"statements":[]}`, "missing node type"},
+		{`{"type":"Program","statements":[{"type":"Widget"}]}`, `Program.Statements: [0]: unknown node type "Widget"`},
+		{`{"type":"Identifier","value":"x"}`, "expected Program, got Identifier"},
+		{`{"type":"Program","statements":[{"type":"Identifier","value":"x"}]}`, "Program.Statements: [0]: Identifier cannot be used as ast.Statement"},
+		{`{"type":"IntegerLiteral","big":"12x"}`, `IntegerLiteral.Big: invalid integer "12x"`},
+	}
+
+	for _, tt := range tests {
+		_, err := ast.UnmarshalProgram([]byte(tt.input))
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("UnmarshalProgram(%s) error wrong. expected=%q, got=%v", tt.input, tt.expected, err)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package ast
+
+import (
+	"bytes"
+	"gomonkey/token"
+	"strings"
+)
+
+type Pattern interface {
+	Node
+	patternNode()
+}
+
+func (ident *Identifier) patternNode() {}
+
+type WildcardPattern struct {
+	Token token.Token
+}
+
+func (wp *WildcardPattern) patternNode() {}
+func (wp *WildcardPattern) TokenLiteral() string {
+	return wp.Token.Literal
+}
+func (wp *WildcardPattern) String() string {
+	return wp.Token.Literal
+}
+
+type LiteralPattern struct {
+	Token token.Token
+	Value Expression
+}
+
+func (lp *LiteralPattern) patternNode() {}
+func (lp *LiteralPattern) TokenLiteral() string {
+	return lp.Token.Literal
+}
+func (lp *LiteralPattern) String() string {
+	return lp.Value.String()
+}
+
+type ArrayPattern struct {
+	Token    token.Token
+	Elements []Pattern
+	End      token.Token
+}
+
+func (ap *ArrayPattern) patternNode() {}
+func (ap *ArrayPattern) TokenLiteral() string {
+	return ap.Token.Literal
+}
+func (ap *ArrayPattern) String() string {
+	var out bytes.Buffer
+
+	elements := []string{}
+	for _, element := range ap.Elements {
+		elements = append(elements, element.String())
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+
+	return out.String()
+}
+
+type HashPattern struct {
+	Token token.Token
+	Pairs []*HashPatternPair
+	End   token.Token
+}
+
+func (hp *HashPattern) patternNode() {}
+func (hp *HashPattern) TokenLiteral() string {
+	return hp.Token.Literal
+}
+func (hp *HashPattern) String() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+	for _, pair := range hp.Pairs {
+		pairs = append(pairs, pair.String())
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+type HashPatternPair struct {
+	Key   Expression
+	Value Pattern
+}
+
+func (hpp *HashPatternPair) String() string {
+	if key, ok := hpp.Key.(*Identifier); ok && bindsName(hpp.Value, key.Value) {
+		return hpp.Value.String()
+	}
+	return hpp.Key.String() + ": " + hpp.Value.String()
+}
+
+func bindsName(pattern Pattern, name string) bool {
+	switch pattern := pattern.(type) {
+	case *Identifier:
+		return pattern.Value == name
+	case *DefaultPattern:
+		return bindsName(pattern.Target, name)
+	}
+	return false
+}
+
+type RestPattern struct {
+	Token token.Token
+	Name  *Identifier
+}
+
+func (rp *RestPattern) patternNode() {}
+func (rp *RestPattern) TokenLiteral() string {
+	return rp.Token.Literal
+}
+func (rp *RestPattern) String() string {
+	return rp.Token.Literal + rp.Name.String()
+}
+
+type DefaultPattern struct {
+	Token   token.Token
+	Target  Pattern
+	Default Expression
+}
+
+func (dp *DefaultPattern) patternNode() {}
+func (dp *DefaultPattern) TokenLiteral() string {
+	return dp.Token.Literal
+}
+func (dp *DefaultPattern) String() string {
+	return dp.Target.String() + " = " + dp.Default.String()
+}
+
+func BoundNames(pattern Pattern) []*Identifier {
+	names := []*Identifier{}
+
+	switch pattern := pattern.(type) {
+	case *Identifier:
+		names = append(names, pattern)
+	case *ArrayPattern:
+		for _, element := range pattern.Elements {
+			names = append(names, BoundNames(element)...)
+		}
+	case *HashPattern:
+		for _, pair := range pattern.Pairs {
+			names = append(names, BoundNames(pair.Value)...)
+		}
+	case *RestPattern:
+		if pattern.Name != nil {
+			names = append(names, pattern.Name)
+		}
+	case *DefaultPattern:
+		names = append(names, BoundNames(pattern.Target)...)
+	}
+
+	return names
+}
@@ -0,0 +1,54 @@
+package ast
+
+import (
+	"bytes"
+	"gomonkey/token"
+	"strings"
+)
+
+type TypeExpression interface {
+	Node
+	typeNode()
+}
+
+type NamedType struct {
+	Token token.Token
+	Name  string
+}
+
+func (nt *NamedType) typeNode() {}
+func (nt *NamedType) TokenLiteral() string {
+	return nt.Token.Literal
+}
+
+func (nt *NamedType) String() string {
+	return nt.Name
+}
+
+type FunctionType struct {
+	Token      token.Token
+	Parameters []TypeExpression
+	Return     TypeExpression
+}
+
+func (ft *FunctionType) typeNode() {}
+func (ft *FunctionType) TokenLiteral() string {
+	return ft.Token.Literal
+}
+
+func (ft *FunctionType) String() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for _, p := range ft.Parameters {
+		params = append(params, p.String())
+	}
+
+	out.WriteString(ft.TokenLiteral())
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") -> ")
+	out.WriteString(ft.Return.String())
+
+	return out.String()
+}
@@ -0,0 +1,165 @@
+package ast
+
+func Inspect(node Node, f func(Node) bool) {
+	if node == nil || !f(node) {
+		return
+	}
+
+	switch node := node.(type) {
+	case *Program:
+		for _, statement := range node.Statements {
+			Inspect(statement, f)
+		}
+	case *BlockStatement:
+		for _, statement := range node.Statements {
+			Inspect(statement, f)
+		}
+	case *LetStatement:
+		if node.Name != nil {
+			Inspect(node.Name, f)
+		}
+		inspectPattern(node.Pattern, f)
+		inspectType(node.Type, f)
+		inspectExpression(node.Value, f)
+	case *Identifier:
+		inspectType(node.Type, f)
+	case *ReturnStatement:
+		inspectExpression(node.ReturnValue, f)
+	case *ExpressionStatement:
+		inspectExpression(node.Expression, f)
+	case *ThrowStatement:
+		inspectExpression(node.Value, f)
+	case *WhileStatement:
+		inspectExpression(node.Condition, f)
+		inspectBlock(node.Body, f)
+	case *ForStatement:
+		if node.Init != nil {
+			Inspect(node.Init, f)
+		}
+		inspectExpression(node.Condition, f)
+		inspectExpression(node.Update, f)
+		inspectBlock(node.Body, f)
+	case *ForInStatement:
+		if node.Variable != nil {
+			Inspect(node.Variable, f)
+		}
+		inspectExpression(node.Iterable, f)
+		inspectBlock(node.Body, f)
+	case *ImportStatement:
+		if node.Path != nil {
+			Inspect(node.Path, f)
+		}
+		if node.Alias != nil {
+			Inspect(node.Alias, f)
+		}
+	case *ExportStatement:
+		if node.Statement != nil {
+			Inspect(node.Statement, f)
+		}
+	case *PrefixExpression:
+		inspectExpression(node.Right, f)
+	case *GroupedExpression:
+		inspectExpression(node.Expression, f)
+	case *InfixExpression:
+		inspectExpression(node.Left, f)
+		inspectExpression(node.Right, f)
+	case *AssignExpression:
+		inspectExpression(node.Target, f)
+		inspectExpression(node.Value, f)
+	case *IfExpression:
+		inspectExpression(node.Condition, f)
+		inspectBlock(node.Consequence, f)
+		inspectBlock(node.Alternative, f)
+	case *ConditionalExpression:
+		inspectExpression(node.Condition, f)
+		inspectExpression(node.Consequence, f)
+		inspectExpression(node.Alternative, f)
+	case *FunctionLiteral:
+		for _, parameter := range node.Parameters {
+			inspectPattern(parameter, f)
+		}
+		inspectType(node.ReturnType, f)
+		inspectBlock(node.Body, f)
+	case *CallExpression:
+		inspectExpression(node.Function, f)
+		for _, argument := range node.Arguments {
+			inspectExpression(argument, f)
+		}
+	case *IndexExpression:
+		inspectExpression(node.Left, f)
+		inspectExpression(node.Index, f)
+	case *MemberExpression:
+		inspectExpression(node.Object, f)
+		if node.Property != nil {
+			Inspect(node.Property, f)
+		}
+	case *SpreadExpression:
+		inspectExpression(node.Value, f)
+	case *NamedArgument:
+		if node.Name != nil {
+			Inspect(node.Name, f)
+		}
+		inspectExpression(node.Value, f)
+	case *MatchExpression:
+		inspectExpression(node.Subject, f)
+		for _, arm := range node.Arms {
+			inspectPattern(arm.Pattern, f)
+			inspectExpression(arm.Guard, f)
+			inspectExpression(arm.Body, f)
+		}
+	case *TryExpression:
+		inspectBlock(node.Block, f)
+		if node.CatchParameter != nil {
+			Inspect(node.CatchParameter, f)
+		}
+		inspectBlock(node.Catch, f)
+		inspectBlock(node.Finally, f)
+	case *LiteralPattern:
+		inspectExpression(node.Value, f)
+	case *ArrayPattern:
+		for _, element := range node.Elements {
+			inspectPattern(element, f)
+		}
+	case *HashPattern:
+		for _, pair := range node.Pairs {
+			inspectExpression(pair.Key, f)
+			inspectPattern(pair.Value, f)
+		}
+	case *RestPattern:
+		if node.Name != nil {
+			Inspect(node.Name, f)
+		}
+	case *DefaultPattern:
+		inspectPattern(node.Target, f)
+		inspectExpression(node.Default, f)
+	case *FunctionType:
+		for _, parameter := range node.Parameters {
+			inspectType(parameter, f)
+		}
+		inspectType(node.Return, f)
+	}
+}
+
+func inspectExpression(expression Expression, f func(Node) bool) {
+	if expression != nil {
+		Inspect(expression, f)
+	}
+}
+
+func inspectPattern(pattern Pattern, f func(Node) bool) {
+	if pattern != nil {
+		Inspect(pattern, f)
+	}
+}
+
+func inspectBlock(block *BlockStatement, f func(Node) bool) {
+	if block != nil {
+		Inspect(block, f)
+	}
+}
+
+func inspectType(typ TypeExpression, f func(Node) bool) {
+	if typ != nil {
+		Inspect(typ, f)
+	}
+}
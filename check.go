@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"gomonkey/ast"
+	"gomonkey/module"
+	"gomonkey/resolver"
+	"gomonkey/types"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+func runCheck(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	infer := flags.Bool("infer", false, "infer types without annotations and print every top-level binding")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		fmt.Fprintf(stderr, "usage: gomonkey check [-infer] file...\n")
+		return 2
+	}
+
+	status := 0
+	checked := map[string]bool{}
+	for _, file := range flags.Args() {
+		root, modules, err := loadModules(file)
+		if err != nil {
+			printLoadError(stderr, "check", root, err)
+			status = 2
+			continue
+		}
+
+		for _, loaded := range modules {
+			path := filepath.Join(root, loaded.Path)
+			if checked[path] {
+				continue
+			}
+			checked[path] = true
+
+			if checkProgram(path, loaded.Program, *infer, stdout) && status == 0 {
+				status = 1
+			}
+		}
+	}
+
+	return status
+}
+
+func checkProgram(file string, program *ast.Program, infer bool, stdout io.Writer) bool {
+	failed := false
+	for _, diagnostic := range resolver.Resolve(program).Errors() {
+		fmt.Fprintf(stdout, "%s:%d:%d: %s\n", file, diagnostic.Line, diagnostic.Column, diagnostic.Message)
+		failed = true
+	}
+
+	var diagnostics []types.Diagnostic
+	if infer {
+		inference := types.Infer(program)
+		for _, binding := range inference.Bindings {
+			fmt.Fprintf(stdout, "%s:%d:%d: %s: %s\n", file, binding.Line, binding.Column, binding.Name, binding.Type)
+		}
+		diagnostics = inference.Diagnostics
+	} else {
+		diagnostics = types.Check(program).Diagnostics
+	}
+
+	for _, diagnostic := range diagnostics {
+		fmt.Fprintf(stdout, "%s:%s\n", file, diagnostic)
+		failed = true
+	}
+
+	return failed
+}
+
+func loadModules(file string) (string, []*module.Module, error) {
+	root, name := ".", filepath.ToSlash(filepath.Clean(file))
+	if !fs.ValidPath(name) {
+		root, name = filepath.Dir(file), filepath.Base(file)
+	}
+
+	entry, err := module.NewLoader(os.DirFS(root)).Load(name)
+	if err != nil {
+		return root, nil, err
+	}
+
+	modules := []*module.Module{}
+	seen := map[*module.Module]bool{}
+
+	var visit func(current *module.Module)
+	visit = func(current *module.Module) {
+		if seen[current] {
+			return
+		}
+		seen[current] = true
+		modules = append(modules, current)
+
+		for _, statement := range current.Program.Statements {
+			if statement, ok := statement.(*ast.ImportStatement); ok {
+				visit(current.Imports[statement.Alias.Value])
+			}
+		}
+	}
+	visit(entry)
+
+	return root, modules, nil
+}
+
+func printLoadError(stderr io.Writer, command string, root string, err error) {
+	var parseErr *module.ParseError
+	if errors.As(err, &parseErr) {
+		for _, e := range parseErr.Errors {
+			fmt.Fprintf(stderr, "%s:%s\n", filepath.Join(root, parseErr.Path), e)
+		}
+		return
+	}
+
+	fmt.Fprintf(stderr, "%s: %s\n", command, err)
+}
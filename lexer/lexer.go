@@ -2,6 +2,7 @@ package lexer
 
 import (
 	"gomonkey/token"
+	"strings"
 )
 
 type Lexer struct {
@@ -9,15 +10,23 @@ type Lexer struct {
 	position     int
 	readPosition int
 	char         byte
+	line         int
+	lineStart    int
+	comments     []token.Token
 }
 
 func New(input string) *Lexer {
-	lexer := &Lexer{input: input}
+	lexer := &Lexer{input: input, line: 1}
 	lexer.readChar()
 	return lexer
 }
 
 func (lexer *Lexer) readChar() {
+	if lexer.char == '\n' {
+		lexer.line += 1
+		lexer.lineStart = lexer.readPosition
+	}
+
 	if lexer.readPosition >= len(lexer.input) {
 		lexer.char = 0
 	} else {
@@ -28,43 +37,114 @@ func (lexer *Lexer) readChar() {
 }
 
 func (lexer *Lexer) NextToken() token.Token {
-	var tok token.Token
-
 	lexer.skipWhitespace()
 
+	line := lexer.line
+	column := lexer.position - lexer.lineStart + 1
+
+	tok := lexer.readToken()
+	tok.Line = line
+	tok.Column = column
+
+	return tok
+}
+
+func (lexer *Lexer) readToken() token.Token {
+	var tok token.Token
+
 	switch lexer.char {
 	case '=':
 		if lexer.peekChar() == '=' {
-			char := lexer.char
-			lexer.readChar()
-			tok = token.Token{Type: token.EQUAL, Literal: string(char) + string(lexer.char)}
+			tok = lexer.readTwoCharToken(token.EQUAL)
+		} else if lexer.peekChar() == '>' {
+			tok = lexer.readTwoCharToken(token.FAT_ARROW)
 		} else {
 			tok = newToken(token.ASSIGN, lexer.char)
 		}
 	case '+':
-		tok = newToken(token.PLUS, lexer.char)
+		if lexer.peekChar() == '=' {
+			tok = lexer.readTwoCharToken(token.PLUS_ASSIGN)
+		} else {
+			tok = newToken(token.PLUS, lexer.char)
+		}
 	case '-':
-		tok = newToken(token.MINUS, lexer.char)
+		if lexer.peekChar() == '=' {
+			tok = lexer.readTwoCharToken(token.MINUS_ASSIGN)
+		} else if lexer.peekChar() == '>' {
+			tok = lexer.readTwoCharToken(token.ARROW)
+		} else {
+			tok = newToken(token.MINUS, lexer.char)
+		}
 	case '*':
-		tok = newToken(token.ASTERISK, lexer.char)
+		if lexer.peekChar() == '*' {
+			tok = lexer.readTwoCharToken(token.POWER)
+		} else if lexer.peekChar() == '=' {
+			tok = lexer.readTwoCharToken(token.ASTERISK_ASSIGN)
+		} else {
+			tok = newToken(token.ASTERISK, lexer.char)
+		}
 	case '/':
-		tok = newToken(token.SLASH, lexer.char)
+		if lexer.peekChar() == '=' {
+			tok = lexer.readTwoCharToken(token.SLASH_ASSIGN)
+		} else {
+			tok = newToken(token.SLASH, lexer.char)
+		}
+	case '%':
+		tok = newToken(token.PERCENT, lexer.char)
 	case '<':
-		tok = newToken(token.LT, lexer.char)
+		if lexer.peekChar() == '=' {
+			tok = lexer.readTwoCharToken(token.LT_EQUAL)
+		} else if lexer.peekChar() == '<' {
+			tok = lexer.readTwoCharToken(token.LEFT_SHIFT)
+		} else {
+			tok = newToken(token.LT, lexer.char)
+		}
 	case '>':
-		tok = newToken(token.GT, lexer.char)
+		if lexer.peekChar() == '=' {
+			tok = lexer.readTwoCharToken(token.GT_EQUAL)
+		} else if lexer.peekChar() == '>' {
+			tok = lexer.readTwoCharToken(token.RIGHT_SHIFT)
+		} else {
+			tok = newToken(token.GT, lexer.char)
+		}
 	case '!':
 		if lexer.peekChar() == '=' {
-			char := lexer.char
-			lexer.readChar()
-			tok = token.Token{Type: token.NOT_EQUAL, Literal: string(char) + string(lexer.char)}
+			tok = lexer.readTwoCharToken(token.NOT_EQUAL)
 		} else {
 			tok = newToken(token.BANG, lexer.char)
 		}
+	case '&':
+		if lexer.peekChar() == '&' {
+			tok = lexer.readTwoCharToken(token.AND)
+		} else {
+			tok = newToken(token.AMPERSAND, lexer.char)
+		}
+	case '|':
+		if lexer.peekChar() == '|' {
+			tok = lexer.readTwoCharToken(token.OR)
+		} else {
+			tok = newToken(token.PIPE, lexer.char)
+		}
+	case '^':
+		tok = newToken(token.CARET, lexer.char)
+	case '~':
+		tok = newToken(token.TILDE, lexer.char)
 	case ',':
 		tok = newToken(token.COMMA, lexer.char)
 	case ';':
 		tok = newToken(token.SEMICOLON, lexer.char)
+	case ':':
+		tok = newToken(token.COLON, lexer.char)
+	case '.':
+		if strings.HasPrefix(lexer.input[lexer.position:], token.ELLIPSIS) {
+			lexer.readChar()
+			lexer.readChar()
+			tok = token.Token{Type: token.ELLIPSIS, Literal: token.ELLIPSIS}
+		} else {
+			tok = newToken(token.DOT, lexer.char)
+		}
+	case '?':
+		tok = newToken(token.QUESTION, lexer.char)
 	case '(':
 		tok = newToken(token.OPEN_PARENTHESIS, lexer.char)
 	case ')':
@@ -73,6 +153,13 @@ func (lexer *Lexer) NextToken() token.Token {
 		tok = newToken(token.OPEN_CURLY, lexer.char)
 	case '}':
 		tok = newToken(token.CLOSE_CURLY, lexer.char)
+	case '[':
+		tok = newToken(token.OPEN_BRACKET, lexer.char)
+	case ']':
+		tok = newToken(token.CLOSE_BRACKET, lexer.char)
+	case '"':
+		tok.Type = token.STRING
+		tok.Literal = lexer.readString()
 	case 0:
 		tok.Literal = ""
 		tok.Type = token.EOF
@@ -94,6 +181,12 @@ func (lexer *Lexer) NextToken() token.Token {
 	return tok
 }
 
+func (lexer *Lexer) readTwoCharToken(tokenType token.TokenType) token.Token {
+	char := lexer.char
+	lexer.readChar()
+	return token.Token{Type: tokenType, Literal: string(char) + string(lexer.char)}
+}
+
 func (lexer *Lexer) readIdentifier() string {
 	position := lexer.position
 	for isLetter(lexer.char) {
@@ -110,6 +203,17 @@ func (lexer *Lexer) readNumber() string {
 	return lexer.input[position:lexer.position]
 }
 
+func (lexer *Lexer) readString() string {
+	position := lexer.position + 1
+	for {
+		lexer.readChar()
+		if lexer.char == '"' || lexer.char == 0 {
+			break
+		}
+	}
+	return lexer.input[position:lexer.position]
+}
+
 func (lexer *Lexer) peekChar() byte {
 	if lexer.readPosition >= len(lexer.input) {
 		return 0
@@ -118,10 +222,33 @@ func (lexer *Lexer) peekChar() byte {
 	}
 }
 
+func (lexer *Lexer) Comments() []token.Token {
+	return lexer.comments
+}
+
 func (lexer *Lexer) skipWhitespace() {
-	for lexer.char == ' ' || lexer.char == '\t' || lexer.char == '\n' || lexer.char == '\r' {
+	for {
+		switch {
+		case lexer.char == ' ' || lexer.char == '\t' || lexer.char == '\n' || lexer.char == '\r':
+			lexer.readChar()
+		case lexer.char == '/' && lexer.peekChar() == '/':
+			lexer.readComment()
+		default:
+			return
+		}
+	}
+}
+
+func (lexer *Lexer) readComment() {
+	comment := token.Token{Type: token.COMMENT, Line: lexer.line, Column: lexer.position - lexer.lineStart + 1}
+
+	position := lexer.position
+	for lexer.char != '\n' && lexer.char != 0 {
 		lexer.readChar()
 	}
+	comment.Literal = lexer.input[position:lexer.position]
+
+	lexer.comments = append(lexer.comments, comment)
 }
 
 func isLetter(char byte) bool {
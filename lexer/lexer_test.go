@@ -220,3 +220,453 @@ func TestDoubleSymbols(t *testing.T) {
 	}
 
 }
+
+func TestComparisonAndLogicalSymbols(t *testing.T) {
+	input := `a <= b >= c
+						a && b || !c
+						10 % 3`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "a"},
+		{token.LT_EQUAL, "<="},
+		{token.IDENT, "b"},
+		{token.GT_EQUAL, ">="},
+		{token.IDENT, "c"},
+		{token.IDENT, "a"},
+		{token.AND, "&&"},
+		{token.IDENT, "b"},
+		{token.OR, "||"},
+		{token.BANG, "!"},
+		{token.IDENT, "c"},
+		{token.INT, "10"},
+		{token.PERCENT, "%"},
+		{token.INT, "3"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, tokenType := range tests {
+		token := lexer.NextToken()
+
+		if token.Type != tokenType.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tokenType.expectedType, token.Type)
+		}
+
+		if token.Literal != tokenType.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tokenType.expectedLiteral, token.Literal)
+		}
+
+	}
+
+}
+
+func TestBitwiseSymbols(t *testing.T) {
+	input := `a & b | c ^ ~d
+						1 << 2 >> 3
+						a && b <= c`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "a"},
+		{token.AMPERSAND, "&"},
+		{token.IDENT, "b"},
+		{token.PIPE, "|"},
+		{token.IDENT, "c"},
+		{token.CARET, "^"},
+		{token.TILDE, "~"},
+		{token.IDENT, "d"},
+		{token.INT, "1"},
+		{token.LEFT_SHIFT, "<<"},
+		{token.INT, "2"},
+		{token.RIGHT_SHIFT, ">>"},
+		{token.INT, "3"},
+		{token.IDENT, "a"},
+		{token.AND, "&&"},
+		{token.IDENT, "b"},
+		{token.LT_EQUAL, "<="},
+		{token.IDENT, "c"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, tokenType := range tests {
+		token := lexer.NextToken()
+
+		if token.Type != tokenType.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tokenType.expectedType, token.Type)
+		}
+
+		if token.Literal != tokenType.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tokenType.expectedLiteral, token.Literal)
+		}
+
+	}
+
+}
+
+func TestPowerSymbol(t *testing.T) {
+	input := `2 ** 3 * 4`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.INT, "2"},
+		{token.POWER, "**"},
+		{token.INT, "3"},
+		{token.ASTERISK, "*"},
+		{token.INT, "4"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, tokenType := range tests {
+		token := lexer.NextToken()
+
+		if token.Type != tokenType.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tokenType.expectedType, token.Type)
+		}
+
+		if token.Literal != tokenType.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tokenType.expectedLiteral, token.Literal)
+		}
+
+	}
+}
+
+func TestAssignmentSymbols(t *testing.T) {
+	input := `x = 1; x += 2; x -= 3; x *= 4; x /= 5; x ** 2`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "x"},
+		{token.ASSIGN, "="},
+		{token.INT, "1"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "x"},
+		{token.PLUS_ASSIGN, "+="},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "x"},
+		{token.MINUS_ASSIGN, "-="},
+		{token.INT, "3"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "x"},
+		{token.ASTERISK_ASSIGN, "*="},
+		{token.INT, "4"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "x"},
+		{token.SLASH_ASSIGN, "/="},
+		{token.INT, "5"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "x"},
+		{token.POWER, "**"},
+		{token.INT, "2"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, tokenType := range tests {
+		token := lexer.NextToken()
+
+		if token.Type != tokenType.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tokenType.expectedType, token.Type)
+		}
+
+		if token.Literal != tokenType.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tokenType.expectedLiteral, token.Literal)
+		}
+
+	}
+
+}
+
+func TestConditionalSymbols(t *testing.T) {
+	input := `a ? b : c`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "a"},
+		{token.QUESTION, "?"},
+		{token.IDENT, "b"},
+		{token.COLON, ":"},
+		{token.IDENT, "c"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, tokenType := range tests {
+		token := lexer.NextToken()
+
+		if token.Type != tokenType.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tokenType.expectedType, token.Type)
+		}
+
+		if token.Literal != tokenType.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tokenType.expectedLiteral, token.Literal)
+		}
+
+	}
+}
+
+func TestControlFlowKeywords(t *testing.T) {
+	input := `while for in break continue throw try catch finally`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.WHILE, "while"},
+		{token.FOR, "for"},
+		{token.IN, "in"},
+		{token.BREAK, "break"},
+		{token.CONTINUE, "continue"},
+		{token.THROW, "throw"},
+		{token.TRY, "try"},
+		{token.CATCH, "catch"},
+		{token.FINALLY, "finally"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, tokenType := range tests {
+		token := lexer.NextToken()
+
+		if token.Type != tokenType.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tokenType.expectedType, token.Type)
+		}
+
+		if token.Literal != tokenType.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tokenType.expectedLiteral, token.Literal)
+		}
+
+	}
+
+}
+
+func TestMatchSymbols(t *testing.T) {
+	input := `match (x) { "one" => 1, [a, _] => a, _ => "" }`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.MATCH, "match"},
+		{token.OPEN_PARENTHESIS, "("},
+		{token.IDENT, "x"},
+		{token.CLOSE_PARENTHESIS, ")"},
+		{token.OPEN_CURLY, "{"},
+		{token.STRING, "one"},
+		{token.FAT_ARROW, "=>"},
+		{token.INT, "1"},
+		{token.COMMA, ","},
+		{token.OPEN_BRACKET, "["},
+		{token.IDENT, "a"},
+		{token.COMMA, ","},
+		{token.IDENT, "_"},
+		{token.CLOSE_BRACKET, "]"},
+		{token.FAT_ARROW, "=>"},
+		{token.IDENT, "a"},
+		{token.COMMA, ","},
+		{token.IDENT, "_"},
+		{token.FAT_ARROW, "=>"},
+		{token.STRING, ""},
+		{token.CLOSE_CURLY, "}"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, tokenType := range tests {
+		token := lexer.NextToken()
+
+		if token.Type != tokenType.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tokenType.expectedType, token.Type)
+		}
+
+		if token.Literal != tokenType.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tokenType.expectedLiteral, token.Literal)
+		}
+
+	}
+
+}
+
+func TestEllipsis(t *testing.T) {
+	input := `[a, ...rest] ..`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.OPEN_BRACKET, "["},
+		{token.IDENT, "a"},
+		{token.COMMA, ","},
+		{token.ELLIPSIS, "..."},
+		{token.IDENT, "rest"},
+		{token.CLOSE_BRACKET, "]"},
+		{token.DOT, "."},
+		{token.DOT, "."},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, tokenType := range tests {
+		token := lexer.NextToken()
+
+		if token.Type != tokenType.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tokenType.expectedType, token.Type)
+		}
+
+		if token.Literal != tokenType.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tokenType.expectedLiteral, token.Literal)
+		}
+
+	}
+
+}
+
+func TestTypeAnnotationSymbols(t *testing.T) {
+	input := `fn(a: int) -> int { a - -1 }`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.FUNCTION, "fn"},
+		{token.OPEN_PARENTHESIS, "("},
+		{token.IDENT, "a"},
+		{token.COLON, ":"},
+		{token.IDENT, "int"},
+		{token.CLOSE_PARENTHESIS, ")"},
+		{token.ARROW, "->"},
+		{token.IDENT, "int"},
+		{token.OPEN_CURLY, "{"},
+		{token.IDENT, "a"},
+		{token.MINUS, "-"},
+		{token.MINUS, "-"},
+		{token.INT, "1"},
+		{token.CLOSE_CURLY, "}"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, tokenType := range tests {
+		token := lexer.NextToken()
+
+		if token.Type != tokenType.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tokenType.expectedType, token.Type)
+		}
+
+		if token.Literal != tokenType.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tokenType.expectedLiteral, token.Literal)
+		}
+	}
+}
+
+func TestTokenPositions(t *testing.T) {
+	input := "let x = 5;\n  add(x,\n\t\"a\nb\") == 10"
+
+	tests := []struct {
+		expectedType   token.TokenType
+		expectedLine   int
+		expectedColumn int
+	}{
+		{token.LET, 1, 1},
+		{token.IDENT, 1, 5},
+		{token.ASSIGN, 1, 7},
+		{token.INT, 1, 9},
+		{token.SEMICOLON, 1, 10},
+		{token.IDENT, 2, 3},
+		{token.OPEN_PARENTHESIS, 2, 6},
+		{token.IDENT, 2, 7},
+		{token.COMMA, 2, 8},
+		{token.STRING, 3, 2},
+		{token.CLOSE_PARENTHESIS, 4, 3},
+		{token.EQUAL, 4, 5},
+		{token.INT, 4, 8},
+		{token.EOF, 4, 10},
+	}
+
+	lexer := New(input)
+
+	for i, test := range tests {
+		token := lexer.NextToken()
+
+		if token.Type != test.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, test.expectedType, token.Type)
+		}
+
+		if token.Line != test.expectedLine || token.Column != test.expectedColumn {
+			t.Fatalf("tests[%d] - position wrong. expected=%d:%d, got=%d:%d", i,
+				test.expectedLine, test.expectedColumn, token.Line, token.Column)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	input := "// leading\nlet x = 10 / 2; // trailing\n//\nx"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.LET, "let"},
+		{token.IDENT, "x"},
+		{token.ASSIGN, "="},
+		{token.INT, "10"},
+		{token.SLASH, "/"},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "x"},
+		{token.EOF, ""},
+	}
+
+	lexer := New(input)
+
+	for i, test := range tests {
+		token := lexer.NextToken()
+
+		if token.Type != test.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, test.expectedType, token.Type)
+		}
+
+		if token.Literal != test.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, test.expectedLiteral, token.Literal)
+		}
+	}
+
+	expected := []token.Token{
+		{Type: token.COMMENT, Literal: "// leading", Line: 1, Column: 1},
+		{Type: token.COMMENT, Literal: "// trailing", Line: 2, Column: 17},
+		{Type: token.COMMENT, Literal: "//", Line: 3, Column: 1},
+	}
+
+	comments := lexer.Comments()
+	if len(comments) != len(expected) {
+		t.Fatalf("wrong number of comments. expected=%d, got=%d", len(expected), len(comments))
+	}
+
+	for i, comment := range comments {
+		if comment != expected[i] {
+			t.Errorf("comments[%d] wrong. expected=%+v, got=%+v", i, expected[i], comment)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"gomonkey/lint"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const defaultLintConfig = ".monkeylint.json"
+
+type lintProblem struct {
+	File string `json:"file"`
+	lint.Problem
+}
+
+func runLint(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := flags.String("config", "", "path to a JSON lint config (default "+defaultLintConfig+" if present)")
+	asJSON := flags.Bool("json", false, "print problems as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		fmt.Fprintf(stderr, "usage: gomonkey lint [-config file] [-json] file...\n")
+		return 2
+	}
+
+	config, err := loadLintConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "lint: %s\n", err)
+		return 2
+	}
+
+	problems := []lintProblem{}
+	failed := false
+
+	linted := map[string]bool{}
+	for _, file := range flags.Args() {
+		root, modules, err := loadModules(file)
+		if err != nil {
+			printLoadError(stderr, "lint", root, err)
+			failed = true
+			continue
+		}
+
+		for _, loaded := range modules {
+			path := filepath.Join(root, loaded.Path)
+			if linted[path] {
+				continue
+			}
+			linted[path] = true
+
+			found, err := lint.Lint(loaded.Source, config)
+			if err != nil {
+				fmt.Fprintf(stderr, "%s:%s\n", path, err)
+				failed = true
+				continue
+			}
+
+			for _, problem := range found {
+				problems = append(problems, lintProblem{File: path, Problem: problem})
+			}
+		}
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(problems)
+	} else {
+		for _, problem := range problems {
+			fmt.Fprintf(stdout, "%s:%s\n", problem.File, problem.Problem)
+		}
+	}
+
+	switch {
+	case failed:
+		return 2
+	case len(problems) > 0:
+		return 1
+	}
+	return 0
+}
+
+func loadLintConfig(path string) (lint.Config, error) {
+	explicit := path != ""
+	if !explicit {
+		path = defaultLintConfig
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return lint.Config{}, nil
+	}
+	if err != nil {
+		return lint.Config{}, err
+	}
+	defer file.Close()
+
+	config, err := lint.LoadConfig(file)
+	if err != nil {
+		return lint.Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
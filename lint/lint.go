@@ -0,0 +1,136 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"gomonkey/lexer"
+	"gomonkey/parser"
+	"gomonkey/token"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	UnusedLet         = "unused-let"
+	UnusedParameter   = "unused-parameter"
+	Unreachable       = "unreachable"
+	ConstantCondition = "constant-condition"
+	SelfComparison    = "self-comparison"
+	EmptyBlock        = "empty-block"
+)
+
+var Rules = []string{UnusedLet, UnusedParameter, Unreachable, ConstantCondition, SelfComparison, EmptyBlock}
+
+type Problem struct {
+	Rule    string `json:"rule"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (problem Problem) String() string {
+	return fmt.Sprintf("%d:%d: %s (%s)", problem.Line, problem.Column, problem.Message, problem.Rule)
+}
+
+type Config struct {
+	Rules map[string]bool `json:"rules"`
+}
+
+func (config Config) Enabled(rule string) bool {
+	enabled, ok := config.Rules[rule]
+	return !ok || enabled
+}
+
+func LoadConfig(r io.Reader) (Config, error) {
+	config := Config{}
+
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return Config{}, err
+	}
+
+	for rule := range config.Rules {
+		if !isRule(rule) {
+			return Config{}, fmt.Errorf("unknown lint rule %q", rule)
+		}
+	}
+
+	return config, nil
+}
+
+type ParseError struct {
+	Errors []parser.Error
+}
+
+func (err *ParseError) Error() string {
+	messages := []string{}
+	for _, e := range err.Errors {
+		messages = append(messages, e.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+func Lint(input string, config Config) ([]Problem, error) {
+	lexer := lexer.New(input)
+	parser := parser.New(lexer)
+	program := parser.ParseProgram()
+	if len(parser.PositionedErrors()) > 0 {
+		return nil, &ParseError{Errors: parser.PositionedErrors()}
+	}
+
+	ignored := ignoredRules(input, lexer.Comments())
+
+	problems := []Problem{}
+	for _, problem := range check(program) {
+		if !config.Enabled(problem.Rule) || ignored[problem.Line][problem.Rule] {
+			continue
+		}
+		problems = append(problems, problem)
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Column < problems[j].Column
+	})
+
+	return problems, nil
+}
+
+func ignoredRules(input string, comments []token.Token) map[int]map[string]bool {
+	lines := strings.Split(input, "\n")
+	ignored := map[int]map[string]bool{}
+
+	for _, comment := range comments {
+		fields := strings.Fields(strings.TrimPrefix(comment.Literal, "//"))
+		if len(fields) < 2 || fields[0] != "lint:ignore" {
+			continue
+		}
+
+		line := comment.Line
+		if strings.TrimSpace(lines[line-1][:comment.Column-1]) == "" {
+			line++
+		}
+
+		if ignored[line] == nil {
+			ignored[line] = map[string]bool{}
+		}
+		for _, rule := range strings.Split(fields[1], ",") {
+			ignored[line][rule] = true
+		}
+	}
+
+	return ignored
+}
+
+func isRule(name string) bool {
+	for _, rule := range Rules {
+		if rule == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"let a = 1; a;", []string{}},
+		{"let a = 1;", []string{"1:5: a is declared but never used (unused-let)"}},
+		{"let [a, {b: c}] = d; a;", []string{"1:13: c is declared but never used (unused-let)"}},
+		{"let _a = 1; export let b = 2;", []string{}},
+		{"let f = fn(x, y) { x }; f;", []string{"1:15: parameter y is never used (unused-parameter)"}},
+		{"let f = fn(_x, ...rest) { rest }; f;", []string{}},
+		{"let f = fn(x) { fn() { x } }; f;", []string{}},
+		{"fn() { return 1; 2; 3 };", []string{"1:18: unreachable code after return (unreachable)"}},
+		{"while (x) { break; x }", []string{"1:20: unreachable code after break (unreachable)"}},
+		{"throw 1; 2;", []string{"1:10: unreachable code after throw (unreachable)"}},
+		{"if (true) { 1 };", []string{"1:1: if condition true is always true (constant-condition)"}},
+		{"if (0) { 1 };", []string{"1:1: if condition 0 is always true (constant-condition)"}},
+		{"if ((true)) { 1 };", []string{"1:1: if condition true is always true (constant-condition)"}},
+		{"if (x) { 1 } else if (false) { 2 };", []string{"1:19: if condition false is always false (constant-condition)"}},
+		{"x == x;", []string{"1:3: x is compared with itself (self-comparison)"}},
+		{"a.b <= a.b;", []string{"1:5: a.b is compared with itself (self-comparison)"}},
+		{"f() == f(); x + x; x == y;", []string{}},
+		{"if (x) { } else { 1 };", []string{"1:8: empty block (empty-block)"}},
+		{"try { 1 } finally { };", []string{"1:19: empty block (empty-block)"}},
+		{"let f = fn() { }; f;", []string{}},
+	}
+
+	for _, tt := range tests {
+		problems, err := Lint(tt.input, Config{})
+		if err != nil {
+			t.Fatalf("input %q: Lint returned error: %s", tt.input, err)
+		}
+
+		actual := []string{}
+		for _, problem := range problems {
+			actual = append(actual, problem.String())
+		}
+
+		if strings.Join(actual, "\n") != strings.Join(tt.expected, "\n") {
+			t.Errorf("input %q: wrong problems.\nexpected=%q\ngot=%q", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+func TestConfigDisablesRules(t *testing.T) {
+	config, err := LoadConfig(strings.NewReader(`{"rules": {"unused-let": false, "empty-block": true}}`))
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %s", err)
+	}
+
+	problems, err := Lint("let a = 1; if (a) { };", config)
+	if err != nil {
+		t.Fatalf("Lint returned error: %s", err)
+	}
+
+	if len(problems) != 1 || problems[0].Rule != EmptyBlock {
+		t.Errorf("wrong problems. got=%v", problems)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"rules": {"no-such-rule": true}}`, `unknown lint rule "no-such-rule"`},
+		{`{"rulez": {}}`, `json: unknown field "rulez"`},
+	}
+
+	for _, tt := range tests {
+		_, err := LoadConfig(strings.NewReader(tt.input))
+		if err == nil || err.Error() != tt.expected {
+			t.Errorf("input %q: wrong error. expected=%q, got=%v", tt.input, tt.expected, err)
+		}
+	}
+}
+
+func TestIgnoreComments(t *testing.T) {
+	input := `
+// lint:ignore unused-let,self-comparison kept for the debugger
+let a = a == a;
+let b = 1; // lint:ignore unused-let
+let c = 1; // lint:ignore empty-block
+// lint:ignore
+let d = 1;
+`
+	problems, err := Lint(input, Config{})
+	if err != nil {
+		t.Fatalf("Lint returned error: %s", err)
+	}
+
+	expected := []string{
+		"5:5: c is declared but never used (unused-let)",
+		"7:5: d is declared but never used (unused-let)",
+	}
+
+	if len(problems) != len(expected) {
+		t.Fatalf("wrong number of problems. expected=%d, got=%v", len(expected), problems)
+	}
+
+	for i, problem := range problems {
+		if problem.String() != expected[i] {
+			t.Errorf("problems[%d] wrong. expected=%q, got=%q", i, expected[i], problem.String())
+		}
+	}
+}
+
+func TestLintParseError(t *testing.T) {
+	_, err := Lint("let = 1;", Config{})
+
+	parseErr, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("expected *ParseError. got=%T (%v)", err, err)
+	}
+
+	expected := "1:5: expected next token to be IDENT, got = instead; 1:5: no prefix parse function for = found"
+	if parseErr.Error() != expected {
+		t.Errorf("parse error wrong. expected=%q, got=%q", expected, parseErr.Error())
+	}
+}
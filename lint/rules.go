@@ -0,0 +1,167 @@
+package lint
+
+import (
+	"fmt"
+	"gomonkey/ast"
+	"gomonkey/resolver"
+	"gomonkey/token"
+	"strings"
+)
+
+var comparisons = map[string]bool{
+	token.EQUAL:     true,
+	token.NOT_EQUAL: true,
+	token.LT:        true,
+	token.GT:        true,
+	token.LT_EQUAL:  true,
+	token.GT_EQUAL:  true,
+}
+
+type checker struct {
+	used     map[*ast.Identifier]bool
+	exported map[*ast.LetStatement]bool
+	problems []Problem
+}
+
+func check(program *ast.Program) []Problem {
+	c := &checker{
+		used:     map[*ast.Identifier]bool{},
+		exported: map[*ast.LetStatement]bool{},
+		problems: []Problem{},
+	}
+
+	for _, symbol := range resolver.Resolve(program).Uses {
+		if symbol.Definition != nil {
+			c.used[symbol.Definition] = true
+		}
+	}
+
+	c.checkUnreachable(program.Statements)
+
+	ast.Inspect(program, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.ExportStatement:
+			c.exported[node.Statement] = true
+		case *ast.LetStatement:
+			c.checkLet(node)
+		case *ast.FunctionLiteral:
+			c.checkParameters(node)
+		case *ast.BlockStatement:
+			c.checkUnreachable(node.Statements)
+		case *ast.IfExpression:
+			c.checkCondition(node)
+			c.checkEmpty(node.Consequence, node.Alternative)
+		case *ast.WhileStatement:
+			c.checkEmpty(node.Body)
+		case *ast.ForStatement:
+			c.checkEmpty(node.Body)
+		case *ast.ForInStatement:
+			c.checkEmpty(node.Body)
+		case *ast.TryExpression:
+			c.checkEmpty(node.Block, node.Catch, node.Finally)
+		case *ast.InfixExpression:
+			c.checkSelfComparison(node)
+		}
+		return true
+	})
+
+	return c.problems
+}
+
+func (c *checker) checkLet(statement *ast.LetStatement) {
+	if c.exported[statement] {
+		return
+	}
+
+	names := []*ast.Identifier{statement.Name}
+	if statement.Pattern != nil {
+		names = ast.BoundNames(statement.Pattern)
+	}
+
+	for _, name := range names {
+		if name != nil && !c.used[name] && !strings.HasPrefix(name.Value, "_") {
+			c.report(UnusedLet, name.Token, fmt.Sprintf("%s is declared but never used", name.Value))
+		}
+	}
+}
+
+func (c *checker) checkParameters(literal *ast.FunctionLiteral) {
+	for _, parameter := range literal.Parameters {
+		for _, name := range ast.BoundNames(parameter) {
+			if !c.used[name] && !strings.HasPrefix(name.Value, "_") {
+				c.report(UnusedParameter, name.Token, fmt.Sprintf("parameter %s is never used", name.Value))
+			}
+		}
+	}
+}
+
+func (c *checker) checkUnreachable(statements []ast.Statement) {
+	for i := 0; i < len(statements)-1; i++ {
+		var keyword string
+		switch statements[i].(type) {
+		case *ast.ReturnStatement:
+			keyword = "return"
+		case *ast.ThrowStatement:
+			keyword = "throw"
+		case *ast.BreakStatement:
+			keyword = "break"
+		case *ast.ContinueStatement:
+			keyword = "continue"
+		default:
+			continue
+		}
+
+		c.report(Unreachable, ast.StatementToken(statements[i+1]), fmt.Sprintf("unreachable code after %s", keyword))
+		return
+	}
+}
+
+func (c *checker) checkCondition(expression *ast.IfExpression) {
+	var truthy bool
+	switch condition := ast.Unparen(expression.Condition).(type) {
+	case *ast.Boolean:
+		truthy = condition.Value
+	case *ast.IntegerLiteral, *ast.StringLiteral:
+		truthy = true
+	default:
+		return
+	}
+
+	c.report(ConstantCondition, expression.Token, fmt.Sprintf("if condition %s is always %t", expression.Condition.String(), truthy))
+}
+
+func (c *checker) checkSelfComparison(expression *ast.InfixExpression) {
+	if !comparisons[expression.Operator] || expression.Left == nil || expression.Right == nil {
+		return
+	}
+
+	if expression.Left.String() != expression.Right.String() || !sideEffectFree(expression.Left) {
+		return
+	}
+
+	c.report(SelfComparison, expression.Token, fmt.Sprintf("%s is compared with itself", expression.Left.String()))
+}
+
+func (c *checker) checkEmpty(blocks ...*ast.BlockStatement) {
+	for _, block := range blocks {
+		if block != nil && len(block.Statements) == 0 {
+			c.report(EmptyBlock, block.Token, "empty block")
+		}
+	}
+}
+
+func (c *checker) report(rule string, tok token.Token, message string) {
+	c.problems = append(c.problems, Problem{Rule: rule, Line: tok.Line, Column: tok.Column, Message: message})
+}
+
+func sideEffectFree(expression ast.Expression) bool {
+	free := true
+	ast.Inspect(expression, func(node ast.Node) bool {
+		switch node.(type) {
+		case *ast.CallExpression, *ast.AssignExpression, *ast.FunctionLiteral:
+			free = false
+		}
+		return free
+	})
+	return free
+}
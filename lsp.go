@@ -0,0 +1,23 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"gomonkey/lsp"
+	"io"
+	"os"
+)
+
+func runLsp(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lsp", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if err := lsp.NewServer(os.Stdin, stdout).Run(); err != nil {
+		fmt.Fprintf(stderr, "lsp: %s\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,324 @@
+package lsp
+
+import (
+	"fmt"
+	"gomonkey/ast"
+	"gomonkey/lexer"
+	"gomonkey/parser"
+	"gomonkey/resolver"
+	"gomonkey/token"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+type declaration struct {
+	kind string
+	line int
+}
+
+type document struct {
+	uri          string
+	text         string
+	lines        []string
+	program      *ast.Program
+	parseErrors  []parser.Error
+	resolved     *resolver.Result
+	tokens       []token.Token
+	comments     []token.Token
+	declarations map[*ast.Identifier]declaration
+}
+
+func newDocument(uri string, text string) *document {
+	d := &document{
+		uri:          uri,
+		text:         text,
+		lines:        strings.Split(text, "\n"),
+		declarations: map[*ast.Identifier]declaration{},
+	}
+
+	l := lexer.New(text)
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		d.tokens = append(d.tokens, tok)
+	}
+	d.comments = l.Comments()
+
+	p := parser.New(lexer.New(text))
+	d.program = p.ParseProgram()
+	d.parseErrors = p.PositionedErrors()
+	d.resolved = resolver.Resolve(d.program)
+
+	d.collectDeclarations()
+	return d
+}
+
+func (d *document) collectDeclarations() {
+	declare := func(kind string, line int, names ...*ast.Identifier) {
+		for _, name := range names {
+			if name != nil {
+				d.declarations[name] = declaration{kind: kind, line: line}
+			}
+		}
+	}
+
+	ast.Inspect(d.program, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.LetStatement:
+			if node.Pattern != nil {
+				declare("let", node.Token.Line, ast.BoundNames(node.Pattern)...)
+			} else {
+				declare("let", node.Token.Line, node.Name)
+			}
+		case *ast.FunctionLiteral:
+			for _, parameter := range node.Parameters {
+				declare("parameter", node.Token.Line, ast.BoundNames(parameter)...)
+			}
+		case *ast.ForInStatement:
+			declare("loop variable", node.Token.Line, node.Variable)
+		case *ast.TryExpression:
+			if node.CatchParameter != nil {
+				declare("catch parameter", node.CatchParameter.Token.Line, node.CatchParameter)
+			}
+		case *ast.MatchExpression:
+			for _, arm := range node.Arms {
+				declare("match binding", arm.Token.Line, ast.BoundNames(arm.Pattern)...)
+			}
+		case *ast.ImportStatement:
+			declare("import", node.Token.Line, node.Alias)
+		}
+		return true
+	})
+}
+
+func (d *document) diagnostics() []Diagnostic {
+	diagnostics := []Diagnostic{}
+
+	for _, err := range d.parseErrors {
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    d.pointRange(err.Line, err.Column),
+			Severity: severityError,
+			Source:   "parser",
+			Message:  err.Message,
+		})
+	}
+
+	if len(d.parseErrors) > 0 {
+		return diagnostics
+	}
+
+	for _, diagnostic := range d.resolved.Diagnostics {
+		severity := severityError
+		if diagnostic.Severity == resolver.Warning {
+			severity = severityWarning
+		}
+
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    d.pointRange(diagnostic.Line, diagnostic.Column),
+			Severity: severity,
+			Source:   "resolver",
+			Message:  diagnostic.Message,
+		})
+	}
+
+	return diagnostics
+}
+
+func (d *document) symbolOf(ident *ast.Identifier) *resolver.Symbol {
+	if symbol, ok := d.resolved.Definitions[ident]; ok {
+		return symbol
+	}
+	return d.resolved.Uses[ident]
+}
+
+func (d *document) identifierAt(position Position) *ast.Identifier {
+	line := position.Line + 1
+	if line > len(d.lines) {
+		return nil
+	}
+	column := d.byteColumn(line, position.Character)
+
+	for _, identifiers := range []map[*ast.Identifier]*resolver.Symbol{d.resolved.Definitions, d.resolved.Uses} {
+		for ident := range identifiers {
+			start := ident.Token.Column
+			if ident.Token.Line == line && start <= column && column <= start+len(ident.Value) {
+				return ident
+			}
+		}
+	}
+	return nil
+}
+
+func (d *document) definitionAt(position Position) *ast.Identifier {
+	ident := d.identifierAt(position)
+	if ident == nil {
+		return nil
+	}
+
+	symbol := d.symbolOf(ident)
+	if symbol == nil {
+		return nil
+	}
+	return symbol.Definition
+}
+
+func (d *document) references(definition *ast.Identifier, includeDeclaration bool) []*ast.Identifier {
+	references := []*ast.Identifier{}
+	if includeDeclaration {
+		references = append(references, definition)
+	}
+
+	for ident, symbol := range d.resolved.Uses {
+		if symbol.Definition == definition {
+			references = append(references, ident)
+		}
+	}
+
+	sort.Slice(references, func(i, j int) bool {
+		a, b := references[i].Token, references[j].Token
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return references
+}
+
+func (d *document) hover(ident *ast.Identifier) string {
+	definition := d.symbolOf(ident).Definition
+	declared, ok := d.declarations[definition]
+	if !ok {
+		return ""
+	}
+
+	source := strings.TrimSpace(d.lines[declared.line-1])
+	scope := strings.ToLower(string(d.symbolOf(definition).Scope))
+
+	return fmt.Sprintf("```monkey\n%s\n```\n%s %s (%s)", source, declared.kind, definition.Value, scope)
+}
+
+func (d *document) symbols() []DocumentSymbol {
+	symbols := []DocumentSymbol{}
+
+	for i, statement := range d.program.Statements {
+		if export, ok := statement.(*ast.ExportStatement); ok {
+			statement = export.Statement
+		}
+
+		let, ok := statement.(*ast.LetStatement)
+		if !ok || let == nil {
+			continue
+		}
+
+		end := d.statementEnd(i)
+
+		names := []*ast.Identifier{let.Name}
+		if let.Pattern != nil {
+			names = ast.BoundNames(let.Pattern)
+		}
+
+		for _, name := range names {
+			symbol := DocumentSymbol{
+				Name:           name.Value,
+				Kind:           symbolKindVariable,
+				Range:          Range{Start: d.position(let.Token.Line, let.Token.Column), End: end},
+				SelectionRange: d.identifierRange(name),
+			}
+
+			if function, ok := let.Value.(*ast.FunctionLiteral); ok && let.Name != nil {
+				symbol.Kind = symbolKindFunction
+				symbol.Detail = signature(function)
+			}
+
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	return symbols
+}
+
+func (d *document) statementEnd(index int) Position {
+	var next *token.Token
+	if index+1 < len(d.program.Statements) {
+		tok := ast.StatementToken(d.program.Statements[index+1])
+		next = &tok
+	}
+
+	var last token.Token
+	for _, tok := range d.tokens {
+		if next != nil && !before(tok, *next) {
+			break
+		}
+		last = tok
+	}
+
+	return d.position(last.Line, last.Column+tokenLength(last))
+}
+
+func (d *document) position(line int, column int) Position {
+	if line < 1 || line > len(d.lines) {
+		return Position{Line: max(line-1, 0)}
+	}
+
+	text := d.lines[line-1]
+	offset := min(max(column-1, 0), len(text))
+	return Position{Line: line - 1, Character: len(utf16.Encode([]rune(text[:offset])))}
+}
+
+func (d *document) byteColumn(line int, character int) int {
+	units := 0
+	for offset, r := range d.lines[line-1] {
+		if units >= character {
+			return offset + 1
+		}
+		units += len(utf16.Encode([]rune{r}))
+	}
+	return len(d.lines[line-1]) + 1
+}
+
+func (d *document) identifierRange(ident *ast.Identifier) Range {
+	return Range{
+		Start: d.position(ident.Token.Line, ident.Token.Column),
+		End:   d.position(ident.Token.Line, ident.Token.Column+len(ident.Value)),
+	}
+}
+
+func (d *document) pointRange(line int, column int) Range {
+	start := d.position(line, column)
+	end := start
+
+	for _, tok := range d.tokens {
+		if tok.Line == line && tok.Column == column {
+			end = d.position(line, column+tokenLength(tok))
+			break
+		}
+	}
+	return Range{Start: start, End: end}
+}
+
+func (d *document) location(ident *ast.Identifier) Location {
+	return Location{URI: d.uri, Range: d.identifierRange(ident)}
+}
+
+func signature(function *ast.FunctionLiteral) string {
+	params := []string{}
+	for _, parameter := range function.Parameters {
+		params = append(params, parameter.String())
+	}
+
+	result := "fn(" + strings.Join(params, ", ") + ")"
+	if function.ReturnType != nil {
+		result += " -> " + function.ReturnType.String()
+	}
+	return result
+}
+
+func tokenLength(tok token.Token) int {
+	if tok.Type == token.STRING {
+		return len(tok.Literal) + 2
+	}
+	return len(tok.Literal)
+}
+
+func before(a token.Token, b token.Token) bool {
+	return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+}
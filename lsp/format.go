@@ -0,0 +1,73 @@
+package lsp
+
+import (
+	"gomonkey/lexer"
+	"gomonkey/token"
+	"strings"
+)
+
+var closers = map[token.TokenType]bool{
+	token.CLOSE_PARENTHESIS: true,
+	token.CLOSE_CURLY:       true,
+	token.CLOSE_BRACKET:     true,
+}
+
+var openers = map[token.TokenType]bool{
+	token.OPEN_PARENTHESIS: true,
+	token.OPEN_CURLY:       true,
+	token.OPEN_BRACKET:     true,
+}
+
+func format(text string, options FormattingOptions) string {
+	unit := "\t"
+	if options.InsertSpaces {
+		unit = strings.Repeat(" ", max(options.TabSize, 1))
+	}
+
+	lines := strings.Split(text, "\n")
+	tokens := make([][]token.Token, len(lines)+1)
+	verbatim := make([]bool, len(lines)+1)
+
+	l := lexer.New(text)
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		tokens[tok.Line] = append(tokens[tok.Line], tok)
+
+		for line := tok.Line + 1; line <= tok.Line+strings.Count(tok.Literal, "\n"); line++ {
+			verbatim[line] = true
+		}
+	}
+
+	indents := []int{}
+	for i, line := range lines {
+		number := i + 1
+		if verbatim[number] {
+			continue
+		}
+
+		indent := 0
+		if len(indents) > 0 {
+			indent = indents[len(indents)-1] + 1
+			if len(tokens[number]) > 0 && closers[tokens[number][0].Type] {
+				indent--
+			}
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			lines[i] = ""
+		} else {
+			lines[i] = strings.Repeat(unit, indent) + trimmed
+		}
+
+		for _, tok := range tokens[number] {
+			switch {
+			case openers[tok.Type]:
+				indents = append(indents, indent)
+			case closers[tok.Type] && len(indents) > 0:
+				indents = indents[:len(indents)-1]
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,69 @@
+package lsp
+
+import (
+	"gomonkey/token"
+	"sort"
+	"strings"
+)
+
+var punctuation = map[token.TokenType]bool{
+	token.OPEN_PARENTHESIS:  true,
+	token.CLOSE_PARENTHESIS: true,
+	token.OPEN_CURLY:        true,
+	token.CLOSE_CURLY:       true,
+	token.OPEN_BRACKET:      true,
+	token.CLOSE_BRACKET:     true,
+	token.COMMA:             true,
+	token.SEMICOLON:         true,
+	token.COLON:             true,
+	token.DOT:               true,
+	token.ILLEGAL:           true,
+}
+
+func (d *document) semanticTokens() []int {
+	tokens := append(append([]token.Token{}, d.tokens...), d.comments...)
+	sort.SliceStable(tokens, func(i, j int) bool {
+		return before(tokens[i], tokens[j])
+	})
+
+	data := []int{}
+	previous := Position{}
+
+	for _, tok := range tokens {
+		kind, ok := semanticType(tok)
+		if !ok || strings.Contains(tok.Literal, "\n") {
+			continue
+		}
+
+		start := d.position(tok.Line, tok.Column)
+		end := d.position(tok.Line, tok.Column+tokenLength(tok))
+
+		deltaStart := start.Character
+		if start.Line == previous.Line {
+			deltaStart -= previous.Character
+		}
+
+		data = append(data, start.Line-previous.Line, deltaStart, end.Character-start.Character, kind, 0)
+		previous = start
+	}
+
+	return data
+}
+
+func semanticType(tok token.Token) (int, bool) {
+	switch {
+	case punctuation[tok.Type]:
+		return 0, false
+	case tok.Type == token.COMMENT:
+		return semanticComment, true
+	case tok.Type == token.IDENT:
+		return semanticVariable, true
+	case tok.Type == token.INT:
+		return semanticNumber, true
+	case tok.Type == token.STRING:
+		return semanticString, true
+	case token.LookupIndent(tok.Literal) == tok.Type:
+		return semanticKeyword, true
+	}
+	return semanticOperator, true
+}
@@ -0,0 +1,297 @@
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+type handler func(server *Server, params json.RawMessage) (interface{}, error)
+
+var handlers = map[string]handler{
+	"initialize":                       (*Server).initialize,
+	"initialized":                      (*Server).ignore,
+	"shutdown":                         (*Server).shutdown,
+	"textDocument/didOpen":             (*Server).didOpen,
+	"textDocument/didChange":           (*Server).didChange,
+	"textDocument/didClose":            (*Server).didClose,
+	"textDocument/definition":          (*Server).definition,
+	"textDocument/references":          (*Server).references,
+	"textDocument/hover":               (*Server).hover,
+	"textDocument/documentSymbol":      (*Server).documentSymbol,
+	"textDocument/semanticTokens/full": (*Server).semanticTokens,
+	"textDocument/formatting":          (*Server).formatting,
+}
+
+type Server struct {
+	conn         *conn
+	documents    map[string]*document
+	shuttingDown bool
+}
+
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{conn: newConn(in, out), documents: map[string]*document{}}
+}
+
+func (server *Server) Run() error {
+	for {
+		msg, err := server.conn.read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		var rpcErr *responseError
+		if errors.As(err, &rpcErr) {
+			id := json.RawMessage("null")
+			if err := server.conn.write(&message{ID: &id, Error: rpcErr}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if msg.Method == "exit" {
+			if !server.shuttingDown {
+				return errors.New("exit received before shutdown")
+			}
+			return nil
+		}
+
+		if err := server.handle(msg); err != nil {
+			return err
+		}
+	}
+}
+
+func (server *Server) handle(msg *message) error {
+	handle, ok := handlers[msg.Method]
+
+	var result interface{}
+	var err error
+	if ok {
+		result, err = handle(server, msg.Params)
+	} else {
+		err = &responseError{Code: methodNotFound, Message: fmt.Sprintf("method %q not found", msg.Method)}
+	}
+
+	if msg.ID == nil {
+		return nil
+	}
+
+	response := &message{ID: msg.ID}
+	if err != nil {
+		var rpcErr *responseError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &responseError{Code: invalidRequest, Message: err.Error()}
+		}
+		response.Error = rpcErr
+	} else {
+		response.Result, err = json.Marshal(result)
+		if err != nil {
+			return err
+		}
+	}
+
+	return server.conn.write(response)
+}
+
+func (server *Server) notify(method string, params interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return server.conn.write(&message{Method: method, Params: body})
+}
+
+func (server *Server) document(uri string) (*document, error) {
+	d, ok := server.documents[uri]
+	if !ok {
+		return nil, &responseError{Code: invalidParams, Message: fmt.Sprintf("unknown document %s", uri)}
+	}
+	return d, nil
+}
+
+func (server *Server) publish(d *document) error {
+	return server.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{URI: d.uri, Diagnostics: d.diagnostics()})
+}
+
+func decode(params json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(params, v); err != nil {
+		return &responseError{Code: invalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func (server *Server) initialize(params json.RawMessage) (interface{}, error) {
+	return InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync:           textDocumentSyncFull,
+			DefinitionProvider:         true,
+			ReferencesProvider:         true,
+			HoverProvider:              true,
+			DocumentSymbolProvider:     true,
+			DocumentFormattingProvider: true,
+			SemanticTokensProvider: SemanticTokensOptions{
+				Legend: SemanticTokensLegend{TokenTypes: semanticTokenTypes, TokenModifiers: []string{}},
+				Full:   true,
+			},
+		},
+		ServerInfo: ServerInfo{Name: "gomonkey"},
+	}, nil
+}
+
+func (server *Server) ignore(params json.RawMessage) (interface{}, error) {
+	return nil, nil
+}
+
+func (server *Server) shutdown(params json.RawMessage) (interface{}, error) {
+	server.shuttingDown = true
+	return nil, nil
+}
+
+func (server *Server) didOpen(params json.RawMessage) (interface{}, error) {
+	var p DidOpenTextDocumentParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+
+	d := newDocument(p.TextDocument.URI, p.TextDocument.Text)
+	server.documents[d.uri] = d
+	return nil, server.publish(d)
+}
+
+func (server *Server) didChange(params json.RawMessage) (interface{}, error) {
+	var p DidChangeTextDocumentParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+
+	if len(p.ContentChanges) == 0 {
+		return nil, nil
+	}
+
+	d := newDocument(p.TextDocument.URI, p.ContentChanges[len(p.ContentChanges)-1].Text)
+	server.documents[d.uri] = d
+	return nil, server.publish(d)
+}
+
+func (server *Server) didClose(params json.RawMessage) (interface{}, error) {
+	var p DidCloseTextDocumentParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+
+	delete(server.documents, p.TextDocument.URI)
+	return nil, server.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{URI: p.TextDocument.URI, Diagnostics: []Diagnostic{}})
+}
+
+func (server *Server) definition(params json.RawMessage) (interface{}, error) {
+	var p TextDocumentPositionParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+
+	d, err := server.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	definition := d.definitionAt(p.Position)
+	if definition == nil {
+		return nil, nil
+	}
+	return d.location(definition), nil
+}
+
+func (server *Server) references(params json.RawMessage) (interface{}, error) {
+	var p ReferenceParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+
+	d, err := server.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	locations := []Location{}
+	if definition := d.definitionAt(p.Position); definition != nil {
+		for _, reference := range d.references(definition, p.Context.IncludeDeclaration) {
+			locations = append(locations, d.location(reference))
+		}
+	}
+	return locations, nil
+}
+
+func (server *Server) hover(params json.RawMessage) (interface{}, error) {
+	var p TextDocumentPositionParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+
+	d, err := server.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	ident := d.identifierAt(p.Position)
+	if ident == nil || d.symbolOf(ident).Definition == nil {
+		return nil, nil
+	}
+
+	contents := d.hover(ident)
+	if contents == "" {
+		return nil, nil
+	}
+	return Hover{Contents: MarkupContent{Kind: "markdown", Value: contents}, Range: d.identifierRange(ident)}, nil
+}
+
+func (server *Server) documentSymbol(params json.RawMessage) (interface{}, error) {
+	var p DocumentSymbolParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+
+	d, err := server.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	return d.symbols(), nil
+}
+
+func (server *Server) semanticTokens(params json.RawMessage) (interface{}, error) {
+	var p SemanticTokensParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+
+	d, err := server.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	return SemanticTokens{Data: d.semanticTokens()}, nil
+}
+
+func (server *Server) formatting(params json.RawMessage) (interface{}, error) {
+	var p DocumentFormattingParams
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+
+	d, err := server.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	formatted := format(d.text, p.Options)
+	if formatted == d.text {
+		return []TextEdit{}, nil
+	}
+
+	last := len(d.lines)
+	end := d.position(last, len(d.lines[last-1])+1)
+	return []TextEdit{{Range: Range{End: end}, NewText: formatted}}, nil
+}
@@ -0,0 +1,390 @@
+package lsp
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type client struct {
+	t             *testing.T
+	conn          *conn
+	incoming      chan *message
+	nextID        int
+	notifications []*message
+}
+
+func connect(t *testing.T) (*client, chan error) {
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+
+	c := &client{t: t, conn: newConn(clientIn, clientOut), incoming: make(chan *message, 64)}
+
+	done := make(chan error, 1)
+	go func() {
+		err := NewServer(serverIn, serverOut).Run()
+		serverOut.Close()
+		done <- err
+	}()
+
+	go func() {
+		defer close(c.incoming)
+		for {
+			msg, err := c.conn.read()
+			if err != nil {
+				return
+			}
+			c.incoming <- msg
+		}
+	}()
+
+	t.Cleanup(func() { clientOut.Close() })
+	return c, done
+}
+
+func newClient(t *testing.T) *client {
+	c, _ := connect(t)
+	c.call("initialize", map[string]interface{}{}, nil)
+	c.notify("initialized", map[string]interface{}{})
+	return c
+}
+
+func (c *client) notify(method string, params interface{}) {
+	c.t.Helper()
+
+	body, err := json.Marshal(params)
+	if err != nil {
+		c.t.Fatalf("marshal %s params: %s", method, err)
+	}
+	if err := c.conn.write(&message{Method: method, Params: body}); err != nil {
+		c.t.Fatalf("write %s: %s", method, err)
+	}
+}
+
+func (c *client) call(method string, params interface{}, result interface{}) *responseError {
+	c.t.Helper()
+
+	c.nextID++
+	id := json.RawMessage(strconv.Itoa(c.nextID))
+
+	body, err := json.Marshal(params)
+	if err != nil {
+		c.t.Fatalf("marshal %s params: %s", method, err)
+	}
+	if err := c.conn.write(&message{ID: &id, Method: method, Params: body}); err != nil {
+		c.t.Fatalf("write %s: %s", method, err)
+	}
+
+	for msg := range c.incoming {
+		if msg.ID == nil {
+			c.notifications = append(c.notifications, msg)
+			continue
+		}
+		if string(*msg.ID) != string(id) {
+			c.t.Fatalf("response id wrong. expected=%s, got=%s", id, *msg.ID)
+		}
+
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if result != nil {
+			if err := json.Unmarshal(msg.Result, result); err != nil {
+				c.t.Fatalf("unmarshal %s result %s: %s", method, msg.Result, err)
+			}
+		}
+		return nil
+	}
+
+	c.t.Fatalf("connection closed before response to %s", method)
+	return nil
+}
+
+func (c *client) open(uri string, text string) []Diagnostic {
+	c.t.Helper()
+
+	c.notify("textDocument/didOpen", DidOpenTextDocumentParams{TextDocument: TextDocumentItem{URI: uri, LanguageID: "monkey", Text: text}})
+	return c.diagnostics(uri)
+}
+
+func (c *client) diagnostics(uri string) []Diagnostic {
+	c.t.Helper()
+
+	// A round trip guarantees the server has handled every earlier notification.
+	c.call("$/sync", nil, nil)
+
+	for i := len(c.notifications) - 1; i >= 0; i-- {
+		msg := c.notifications[i]
+		if msg.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+
+		var params PublishDiagnosticsParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			c.t.Fatalf("unmarshal diagnostics: %s", err)
+		}
+		if params.URI == uri {
+			return params.Diagnostics
+		}
+	}
+
+	c.t.Fatalf("no diagnostics published for %s", uri)
+	return nil
+}
+
+func position(line, character int) TextDocumentPositionParams {
+	return TextDocumentPositionParams{TextDocument: TextDocumentIdentifier{URI: "file:///test.mk"}, Position: Position{Line: line, Character: character}}
+}
+
+func span(line, start, end int) Range {
+	return Range{Start: Position{Line: line, Character: start}, End: Position{Line: line, Character: end}}
+}
+
+const source = `let add = fn(a, b) {
+  a + b
+};
+let total = add(1, 2);
+total;
+`
+
+func TestInitialize(t *testing.T) {
+	c, done := connect(t)
+
+	var result InitializeResult
+	c.call("initialize", map[string]interface{}{}, &result)
+
+	capabilities := result.Capabilities
+	if !capabilities.DefinitionProvider || !capabilities.ReferencesProvider || !capabilities.HoverProvider ||
+		!capabilities.DocumentSymbolProvider || !capabilities.DocumentFormattingProvider || !capabilities.SemanticTokensProvider.Full {
+		t.Errorf("missing capabilities. got=%+v", capabilities)
+	}
+	if capabilities.TextDocumentSync != textDocumentSyncFull {
+		t.Errorf("wrong text document sync. got=%d", capabilities.TextDocumentSync)
+	}
+
+	if err := c.call("no/such/method", nil, nil); err == nil || err.Code != methodNotFound {
+		t.Errorf("expected method not found. got=%v", err)
+	}
+
+	c.call("shutdown", nil, nil)
+	c.notify("exit", nil)
+
+	if err := <-done; err != nil {
+		t.Errorf("Run returned error: %s", err)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	c := newClient(t)
+
+	diagnostics := c.open("file:///test.mk", "let x = 1;\nlet x = ;")
+	if len(diagnostics) != 1 {
+		t.Fatalf("wrong number of diagnostics. got=%+v", diagnostics)
+	}
+	if diagnostics[0].Message != "no prefix parse function for ; found" || diagnostics[0].Range != span(1, 8, 9) ||
+		diagnostics[0].Severity != severityError || diagnostics[0].Source != "parser" {
+		t.Errorf("wrong parser diagnostic. got=%+v", diagnostics[0])
+	}
+
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   TextDocumentIdentifier{URI: "file:///test.mk"},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: "let x = 1;\nfn(x) { y };"}},
+	})
+
+	expected := []Diagnostic{
+		{Range: span(1, 3, 4), Severity: severityWarning, Source: "resolver", Message: "declaration of x shadows declaration at 1:5"},
+		{Range: span(1, 8, 9), Severity: severityError, Source: "resolver", Message: "undefined identifier y"},
+	}
+
+	diagnostics = c.diagnostics("file:///test.mk")
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("wrong number of diagnostics. got=%+v", diagnostics)
+	}
+	for i, diagnostic := range diagnostics {
+		if diagnostic != expected[i] {
+			t.Errorf("diagnostics[%d] wrong. expected=%+v, got=%+v", i, expected[i], diagnostic)
+		}
+	}
+
+	c.notify("textDocument/didClose", DidCloseTextDocumentParams{TextDocument: TextDocumentIdentifier{URI: "file:///test.mk"}})
+	if diagnostics := c.diagnostics("file:///test.mk"); len(diagnostics) != 0 {
+		t.Errorf("diagnostics not cleared on close. got=%+v", diagnostics)
+	}
+}
+
+func TestDefinitionAndReferences(t *testing.T) {
+	c := newClient(t)
+	c.open("file:///test.mk", source)
+
+	var location *Location
+	c.call("textDocument/definition", position(1, 2), &location)
+	if location == nil || location.Range != span(0, 13, 14) {
+		t.Errorf("definition of a wrong. got=%+v", location)
+	}
+
+	location = nil
+	c.call("textDocument/definition", position(3, 13), &location)
+	if location == nil || location.Range != span(0, 4, 7) || location.URI != "file:///test.mk" {
+		t.Errorf("definition of add wrong. got=%+v", location)
+	}
+
+	location = nil
+	c.call("textDocument/definition", position(3, 17), &location)
+	if location != nil {
+		t.Errorf("expected no definition for a literal. got=%+v", location)
+	}
+
+	var locations []Location
+	c.call("textDocument/references", ReferenceParams{
+		TextDocumentPositionParams: position(4, 0),
+		Context:                    ReferenceContext{IncludeDeclaration: true},
+	}, &locations)
+
+	if len(locations) != 2 || locations[0].Range != span(3, 4, 9) || locations[1].Range != span(4, 0, 5) {
+		t.Errorf("references of total wrong. got=%+v", locations)
+	}
+
+	c.call("textDocument/references", ReferenceParams{TextDocumentPositionParams: position(0, 16)}, &locations)
+	if len(locations) != 1 || locations[0].Range != span(1, 6, 7) {
+		t.Errorf("references of b wrong. got=%+v", locations)
+	}
+}
+
+func TestHover(t *testing.T) {
+	c := newClient(t)
+	c.open("file:///test.mk", source)
+
+	var hover *Hover
+	c.call("textDocument/hover", position(3, 13), &hover)
+	if hover == nil {
+		t.Fatalf("expected hover for add")
+	}
+
+	expected := "```monkey\nlet add = fn(a, b) {\n```\nlet add (global)"
+	if hover.Contents.Value != expected || hover.Contents.Kind != "markdown" || hover.Range != span(3, 12, 15) {
+		t.Errorf("hover wrong. got=%+v", hover)
+	}
+
+	c.call("textDocument/hover", position(1, 6), &hover)
+	if hover == nil || !strings.HasSuffix(hover.Contents.Value, "parameter b (local)") {
+		t.Errorf("hover for b wrong. got=%+v", hover)
+	}
+
+	hover = nil
+	c.call("textDocument/hover", position(2, 0), &hover)
+	if hover != nil {
+		t.Errorf("expected no hover outside identifiers. got=%+v", hover)
+	}
+}
+
+func TestDocumentSymbols(t *testing.T) {
+	c := newClient(t)
+	c.open("file:///test.mk", source+"export let [x, y] = total;\n")
+
+	var symbols []DocumentSymbol
+	c.call("textDocument/documentSymbol", DocumentSymbolParams{TextDocument: TextDocumentIdentifier{URI: "file:///test.mk"}}, &symbols)
+
+	expected := []DocumentSymbol{
+		{Name: "add", Detail: "fn(a, b)", Kind: symbolKindFunction, Range: Range{Start: Position{0, 0}, End: Position{2, 2}}, SelectionRange: span(0, 4, 7)},
+		{Name: "total", Kind: symbolKindVariable, Range: span(3, 0, 22), SelectionRange: span(3, 4, 9)},
+		{Name: "x", Kind: symbolKindVariable, Range: span(5, 7, 26), SelectionRange: span(5, 12, 13)},
+		{Name: "y", Kind: symbolKindVariable, Range: span(5, 7, 26), SelectionRange: span(5, 15, 16)},
+	}
+
+	if len(symbols) != len(expected) {
+		t.Fatalf("wrong number of symbols. got=%+v", symbols)
+	}
+	for i, symbol := range symbols {
+		if symbol != expected[i] {
+			t.Errorf("symbols[%d] wrong. expected=%+v, got=%+v", i, expected[i], symbol)
+		}
+	}
+}
+
+func TestSemanticTokens(t *testing.T) {
+	c := newClient(t)
+	c.open("file:///test.mk", "let s = \"hi\"; // note\nif (s == 1) { s }")
+
+	var tokens SemanticTokens
+	c.call("textDocument/semanticTokens/full", SemanticTokensParams{TextDocument: TextDocumentIdentifier{URI: "file:///test.mk"}}, &tokens)
+
+	expected := []int{
+		0, 0, 3, semanticKeyword, 0,
+		0, 4, 1, semanticVariable, 0,
+		0, 2, 1, semanticOperator, 0,
+		0, 2, 4, semanticString, 0,
+		0, 6, 7, semanticComment, 0,
+		1, 0, 2, semanticKeyword, 0,
+		0, 4, 1, semanticVariable, 0,
+		0, 2, 2, semanticOperator, 0,
+		0, 3, 1, semanticNumber, 0,
+		0, 5, 1, semanticVariable, 0,
+	}
+
+	if len(tokens.Data) != len(expected) {
+		t.Fatalf("wrong token data length. expected=%v, got=%v", expected, tokens.Data)
+	}
+	for i := range expected {
+		if tokens.Data[i] != expected[i] {
+			t.Fatalf("token data wrong at %d. expected=%v, got=%v", i, expected, tokens.Data)
+		}
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	c := newClient(t)
+
+	input := "let f = fn(a) {\nif (a) {\n    \"x\" \n} else {\nf(fn() {\n1\n})\n}\n};\n  // done\n"
+	c.open("file:///test.mk", input)
+
+	var edits []TextEdit
+	c.call("textDocument/formatting", DocumentFormattingParams{
+		TextDocument: TextDocumentIdentifier{URI: "file:///test.mk"},
+		Options:      FormattingOptions{TabSize: 2, InsertSpaces: true},
+	}, &edits)
+
+	expected := "let f = fn(a) {\n  if (a) {\n    \"x\"\n  } else {\n    f(fn() {\n      1\n    })\n  }\n};\n// done\n"
+
+	if len(edits) != 1 {
+		t.Fatalf("wrong number of edits. got=%+v", edits)
+	}
+	if edits[0].NewText != expected {
+		t.Errorf("formatted text wrong.\nexpected=%q\ngot=%q", expected, edits[0].NewText)
+	}
+	if edits[0].Range != (Range{End: Position{Line: 10, Character: 0}}) {
+		t.Errorf("edit range wrong. got=%+v", edits[0].Range)
+	}
+
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   TextDocumentIdentifier{URI: "file:///test.mk"},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: expected}},
+	})
+	c.call("textDocument/formatting", DocumentFormattingParams{
+		TextDocument: TextDocumentIdentifier{URI: "file:///test.mk"},
+		Options:      FormattingOptions{TabSize: 2, InsertSpaces: true},
+	}, &edits)
+
+	if len(edits) != 0 {
+		t.Errorf("formatting is not idempotent. got=%+v", edits)
+	}
+}
+
+func TestFormatPreservesMultilineStrings(t *testing.T) {
+	input := "if (x) {\nlet s = \"a\n   b\";\n}"
+	expected := "if (x) {\n\tlet s = \"a\n   b\";\n}"
+
+	if formatted := format(input, FormattingOptions{}); formatted != expected {
+		t.Errorf("format wrong.\nexpected=%q\ngot=%q", expected, formatted)
+	}
+}
+
+func TestUnknownDocument(t *testing.T) {
+	c := newClient(t)
+
+	var location *Location
+	err := c.call("textDocument/definition", position(0, 0), &location)
+	if err == nil || err.Code != invalidParams {
+		t.Errorf("expected invalid params error. got=%v", err)
+	}
+}
@@ -3,10 +3,38 @@ package main
 import (
 	"fmt"
 	"gomonkey/repl"
+	"io"
 	"os"
+	"sort"
+	"strings"
 )
 
+var commands = map[string]func(args []string, stdout io.Writer, stderr io.Writer) int{
+	"ast":   runAst,
+	"check": runCheck,
+	"lint":  runLint,
+	"lsp":   runLsp,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		command, ok := commands[os.Args[1]]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown command %q (available: %s)\n", os.Args[1], strings.Join(commandNames(), ", "))
+			os.Exit(2)
+		}
+		os.Exit(command(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	fmt.Printf("Monkey programming language REPL:\n")
 	repl.Start(os.Stdin, os.Stdout)
 }
+
+func commandNames() []string {
+	names := []string{}
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,196 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+	"gomonkey/ast"
+	"gomonkey/lexer"
+	"gomonkey/parser"
+	"gomonkey/resolver"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+type Module struct {
+	Path    string
+	Source  string
+	Program *ast.Program
+	Imports map[string]*Module
+	Exports map[string]*ast.LetStatement
+}
+
+type Loader struct {
+	FS         fs.FS
+	SearchPath []string
+	modules    map[string]*Module
+	loading    []string
+}
+
+type ParseError struct {
+	Path   string
+	Errors []parser.Error
+}
+
+func (err *ParseError) Error() string {
+	messages := []string{}
+	for _, e := range err.Errors {
+		messages = append(messages, fmt.Sprintf("%s:%s", err.Path, e))
+	}
+	return strings.Join(messages, "; ")
+}
+
+func NewLoader(fsys fs.FS, searchPath ...string) *Loader {
+	return &Loader{FS: fsys, SearchPath: searchPath, modules: map[string]*Module{}}
+}
+
+func (loader *Loader) Load(name string) (*Module, error) {
+	name = path.Clean(name)
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "load", Path: name, Err: fs.ErrInvalid}
+	}
+
+	return loader.load(name)
+}
+
+func (loader *Loader) load(name string) (*Module, error) {
+	if module, ok := loader.modules[name]; ok {
+		return module, nil
+	}
+
+	for _, loading := range loader.loading {
+		if loading == name {
+			chain := append(append([]string{}, loader.loading...), name)
+			return nil, fmt.Errorf("import cycle: %s", strings.Join(chain, " -> "))
+		}
+	}
+
+	source, err := fs.ReadFile(loader.FS, name)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := parser.New(lexer.New(string(source)))
+	program := parser.ParseProgram()
+	if len(parser.PositionedErrors()) > 0 {
+		return nil, &ParseError{Path: name, Errors: parser.PositionedErrors()}
+	}
+
+	loader.loading = append(loader.loading, name)
+	defer func() { loader.loading = loader.loading[:len(loader.loading)-1] }()
+
+	module := &Module{
+		Path:    name,
+		Source:  string(source),
+		Program: program,
+		Imports: map[string]*Module{},
+		Exports: map[string]*ast.LetStatement{},
+	}
+
+	for _, statement := range program.Statements {
+		switch statement := statement.(type) {
+		case *ast.ImportStatement:
+			imported, err := loader.loadImport(name, statement)
+			if err != nil {
+				return nil, err
+			}
+			module.Imports[statement.Alias.Value] = imported
+		case *ast.ExportStatement:
+			names := []*ast.Identifier{statement.Statement.Name}
+			if statement.Statement.Pattern != nil {
+				names = ast.BoundNames(statement.Statement.Pattern)
+			}
+			for _, export := range names {
+				module.Exports[export.Value] = statement.Statement
+			}
+		}
+	}
+
+	if err := checkMembers(module); err != nil {
+		return nil, err
+	}
+
+	loader.modules[name] = module
+	return module, nil
+}
+
+func (loader *Loader) loadImport(importer string, statement *ast.ImportStatement) (*Module, error) {
+	resolved, err := loader.resolve(importer, statement.Path.Value)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%d:%d: %w", importer, statement.Token.Line, statement.Token.Column, err)
+	}
+
+	return loader.load(resolved)
+}
+
+func (loader *Loader) resolve(importer string, name string) (string, error) {
+	candidates := []string{path.Join(path.Dir(importer), name)}
+
+	if !strings.HasPrefix(name, "./") && !strings.HasPrefix(name, "../") {
+		for _, dir := range loader.SearchPath {
+			candidates = append(candidates, path.Join(dir, name))
+		}
+	}
+
+	for _, candidate := range candidates {
+		if !fs.ValidPath(candidate) {
+			continue
+		}
+
+		info, err := fs.Stat(loader.FS, candidate)
+		if err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf("cannot find module %q (looked in %s)", name, strings.Join(candidates, ", "))
+}
+
+func checkMembers(module *Module) error {
+	aliases := map[*ast.Identifier]*Module{}
+	for _, statement := range module.Program.Statements {
+		if statement, ok := statement.(*ast.ImportStatement); ok {
+			aliases[statement.Alias] = module.Imports[statement.Alias.Value]
+		}
+	}
+
+	resolved := resolver.Resolve(module.Program)
+	var err error
+
+	ast.Inspect(module.Program, func(node ast.Node) bool {
+		if err != nil {
+			return false
+		}
+
+		member, ok := node.(*ast.MemberExpression)
+		if !ok {
+			return true
+		}
+
+		alias, ok := member.Object.(*ast.Identifier)
+		if !ok {
+			return true
+		}
+
+		symbol, ok := resolved.Uses[alias]
+		if !ok {
+			return true
+		}
+
+		imported, ok := aliases[symbol.Definition]
+		if !ok {
+			return true
+		}
+
+		if _, ok := imported.Exports[member.Property.Value]; !ok {
+			err = fmt.Errorf("%s:%d:%d: module %q has no export named %q", module.Path,
+				member.Property.Token.Line, member.Property.Token.Column, imported.Path, member.Property.Value)
+		}
+		return true
+	})
+
+	return err
+}
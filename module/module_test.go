@@ -0,0 +1,187 @@
+package module
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func mapFS(files map[string]string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for name, source := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(source)}
+	}
+	return fsys
+}
+
+func TestLoadImportsAndExports(t *testing.T) {
+	fsys := mapFS(map[string]string{
+		"main.mk":        `import "lib/math.mk" as m; import "lib/strings.mk" as s; m.add(1, m.two);`,
+		"lib/math.mk":    `import "./strings.mk" as s; export let add = fn(a, b) { a + b }; let pair = s.greeting; export let [one, two] = pair;`,
+		"lib/strings.mk": `export let greeting = "hello";`,
+	})
+
+	main, err := NewLoader(fsys).Load("main.mk")
+	if err != nil {
+		t.Fatalf("Load returned error: %s", err)
+	}
+
+	math, ok := main.Imports["m"]
+	if !ok {
+		t.Fatalf("main has no import m. got=%v", main.Imports)
+	}
+	if math.Path != "lib/math.mk" {
+		t.Errorf("math.Path wrong. got=%s", math.Path)
+	}
+
+	for _, name := range []string{"add", "one", "two"} {
+		if _, ok := math.Exports[name]; !ok {
+			t.Errorf("math does not export %s", name)
+		}
+	}
+
+	if main.Imports["s"] != math.Imports["s"] {
+		t.Errorf("strings.mk was loaded twice instead of being cached")
+	}
+	if _, ok := main.Imports["s"].Exports["greeting"]; !ok {
+		t.Errorf("strings does not export greeting")
+	}
+}
+
+func TestLoadSearchPath(t *testing.T) {
+	fsys := mapFS(map[string]string{
+		"app/main.mk":     `import "util.mk" as u; u.id(1);`,
+		"app/relative.mk": `import "./util.mk" as u;`,
+		"vendor/util.mk":  `export let id = fn(x) { x };`,
+	})
+
+	loader := NewLoader(fsys, "vendor")
+
+	main, err := loader.Load("app/main.mk")
+	if err != nil {
+		t.Fatalf("Load returned error: %s", err)
+	}
+	if main.Imports["u"].Path != "vendor/util.mk" {
+		t.Errorf("util.mk resolved to wrong path. got=%s", main.Imports["u"].Path)
+	}
+
+	_, err = loader.Load("app/relative.mk")
+	if err == nil || !strings.Contains(err.Error(), `cannot find module "./util.mk"`) {
+		t.Errorf("explicitly relative import should not use the search path. got=%v", err)
+	}
+}
+
+func TestLoadFromDirFS(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "lib"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "main.mk"), []byte(`import "lib/a.mk" as a; a.x;`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "lib", "a.mk"), []byte(`export let x = 1;`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	main, err := NewLoader(os.DirFS(root)).Load("main.mk")
+	if err != nil {
+		t.Fatalf("Load returned error: %s", err)
+	}
+	if main.Imports["a"].Path != "lib/a.mk" {
+		t.Errorf("lib/a.mk resolved to wrong path. got=%s", main.Imports["a"].Path)
+	}
+}
+
+func TestLoadChecksOnlyImportedAliases(t *testing.T) {
+	fsys := mapFS(map[string]string{
+		"main.mk": `import "lib.mk" as m; let f = fn(m) { m.anything }; let g = fn() { let m = 1; m.other };`,
+		"lib.mk":  `export let one = 1;`,
+	})
+
+	if _, err := NewLoader(fsys).Load("main.mk"); err != nil {
+		t.Fatalf("Load returned error: %s", err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		entry         string
+		files         map[string]string
+		expectedError string
+	}{
+		{
+			"main.mk",
+			map[string]string{
+				"main.mk": `import "a.mk" as a;`,
+				"a.mk":    `import "b.mk" as b;`,
+				"b.mk":    `import "a.mk" as a;`,
+			},
+			"import cycle: main.mk -> a.mk -> b.mk -> a.mk",
+		},
+		{
+			"main.mk",
+			map[string]string{
+				"main.mk": `import "main.mk" as self;`,
+			},
+			"import cycle: main.mk -> main.mk",
+		},
+		{
+			"main.mk",
+			map[string]string{
+				"main.mk": `let x = 1;
+import "missing.mk" as m;`,
+			},
+			`main.mk:2:1: cannot find module "missing.mk"`,
+		},
+		{
+			"lib/main.mk",
+			map[string]string{
+				"lib/main.mk": `import "../../escape.mk" as m;`,
+			},
+			`lib/main.mk:1:1: cannot find module "../../escape.mk"`,
+		},
+		{
+			"main.mk",
+			map[string]string{
+				"main.mk": `import "a.mk" as a; a.nope;`,
+				"a.mk":    `export let yes = 1; let nope = 2;`,
+			},
+			`main.mk:1:23: module "a.mk" has no export named "nope"`,
+		},
+		{
+			"main.mk",
+			map[string]string{
+				"main.mk": `import "a.mk" as a; let f = fn() { a.nope };`,
+				"a.mk":    `export let yes = 1;`,
+			},
+			`main.mk:1:38: module "a.mk" has no export named "nope"`,
+		},
+		{
+			"main.mk",
+			map[string]string{
+				"main.mk": `import "a.mk" as a;`,
+				"a.mk":    `let = 1;`,
+			},
+			"a.mk:1:5: expected next token to be IDENT, got = instead; a.mk:1:5: no prefix parse function for = found",
+		},
+		{
+			"/main.mk",
+			map[string]string{},
+			"load /main.mk: invalid argument",
+		},
+	}
+
+	for _, test := range tests {
+		_, err := NewLoader(mapFS(test.files)).Load(test.entry)
+		if err == nil {
+			t.Errorf("expected error %q, got none", test.expectedError)
+			continue
+		}
+
+		if !strings.HasPrefix(err.Error(), test.expectedError) {
+			t.Errorf("wrong error. expected=%q, got=%q", test.expectedError, err.Error())
+		}
+	}
+}
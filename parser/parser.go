@@ -5,6 +5,8 @@ import (
 	"gomonkey/ast"
 	"gomonkey/lexer"
 	"gomonkey/token"
+	"io"
+	"math/big"
 	"strconv"
 )
 
@@ -12,58 +14,134 @@ type Parser struct {
 	lexer          *lexer.Lexer
 	currentToken   token.Token
 	peekToken      token.Token
-	errors         []string
+	errors         []Error
+	loopDepth      int
+	tracer         io.Writer
+	traceLevel     int
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
 }
 
+type Error struct {
+	Message string
+	Line    int
+	Column  int
+}
+
+func (err Error) Error() string {
+	return fmt.Sprintf("%d:%d: %s", err.Line, err.Column, err.Message)
+}
+
 const (
 	_ int = iota
 	LOWEST
+	ASSIGN
+	TERNARY
+	LOGICAL_OR
+	LOGICAL_AND
+	BITWISE_OR
+	BITWISE_XOR
+	BITWISE_AND
 	EQUALS
 	LESSGREATER
+	SHIFT
 	SUM
 	PRODUCT
 	PREFIX
+	POWER
 	CALL
 )
 
 var precedences = map[token.TokenType]int{
-	token.EQUAL:     EQUALS,
-	token.NOT_EQUAL: EQUALS,
-	token.LT:        LESSGREATER,
-	token.GT:        LESSGREATER,
-	token.PLUS:      SUM,
-	token.MINUS:     SUM,
-	token.SLASH:     PRODUCT,
-	token.ASTERISK:  PRODUCT,
+	token.ASSIGN:           ASSIGN,
+	token.PLUS_ASSIGN:      ASSIGN,
+	token.MINUS_ASSIGN:     ASSIGN,
+	token.ASTERISK_ASSIGN:  ASSIGN,
+	token.SLASH_ASSIGN:     ASSIGN,
+	token.QUESTION:         TERNARY,
+	token.OR:               LOGICAL_OR,
+	token.AND:              LOGICAL_AND,
+	token.PIPE:             BITWISE_OR,
+	token.CARET:            BITWISE_XOR,
+	token.AMPERSAND:        BITWISE_AND,
+	token.EQUAL:            EQUALS,
+	token.NOT_EQUAL:        EQUALS,
+	token.LT:               LESSGREATER,
+	token.GT:               LESSGREATER,
+	token.LT_EQUAL:         LESSGREATER,
+	token.GT_EQUAL:         LESSGREATER,
+	token.LEFT_SHIFT:       SHIFT,
+	token.RIGHT_SHIFT:      SHIFT,
+	token.PLUS:             SUM,
+	token.MINUS:            SUM,
+	token.SLASH:            PRODUCT,
+	token.ASTERISK:         PRODUCT,
+	token.PERCENT:          PRODUCT,
+	token.POWER:            POWER,
+	token.OPEN_PARENTHESIS: CALL,
+	token.DOT:              CALL,
+	token.OPEN_BRACKET:     CALL,
+}
+
+var rightAssociative = map[token.TokenType]bool{
+	token.ASSIGN:          true,
+	token.PLUS_ASSIGN:     true,
+	token.MINUS_ASSIGN:    true,
+	token.ASTERISK_ASSIGN: true,
+	token.SLASH_ASSIGN:    true,
+	token.QUESTION:        true,
+	token.POWER:           true,
 }
 
 func New(lexer *lexer.Lexer) *Parser {
-	parser := &Parser{lexer: lexer, errors: []string{}}
+	parser := &Parser{lexer: lexer, errors: []Error{}}
 	parser.nextToken()
 	parser.nextToken()
 
 	parser.prefixParseFns = make(map[token.TokenType]prefixParseFn)
 	parser.registerPrefix(token.IDENT, parser.parseIdentifier)
 	parser.registerPrefix(token.INT, parser.parseIntegerLiteral)
+	parser.registerPrefix(token.STRING, parser.parseStringLiteral)
 	parser.registerPrefix(token.BANG, parser.parsePrefixExpression)
 	parser.registerPrefix(token.MINUS, parser.parsePrefixExpression)
+	parser.registerPrefix(token.TILDE, parser.parsePrefixExpression)
 	parser.registerPrefix(token.TRUE, parser.parseBoolean)
 	parser.registerPrefix(token.FALSE, parser.parseBoolean)
 	parser.registerPrefix(token.OPEN_PARENTHESIS, parser.parseGroupExpressions)
 	parser.registerPrefix(token.IF, parser.parseIfExpression)
 	parser.registerPrefix(token.FUNCTION, parser.parseFunctionLiteral)
+	parser.registerPrefix(token.MATCH, parser.parseMatchExpression)
+	parser.registerPrefix(token.TRY, parser.parseTryExpression)
 
 	parser.infixParseFns = make(map[token.TokenType]infixParseFn)
 	parser.registerInfix(token.PLUS, parser.parseInfixExpression)
 	parser.registerInfix(token.MINUS, parser.parseInfixExpression)
 	parser.registerInfix(token.SLASH, parser.parseInfixExpression)
 	parser.registerInfix(token.ASTERISK, parser.parseInfixExpression)
+	parser.registerInfix(token.PERCENT, parser.parseInfixExpression)
+	parser.registerInfix(token.POWER, parser.parseInfixExpression)
 	parser.registerInfix(token.EQUAL, parser.parseInfixExpression)
 	parser.registerInfix(token.NOT_EQUAL, parser.parseInfixExpression)
 	parser.registerInfix(token.LT, parser.parseInfixExpression)
 	parser.registerInfix(token.GT, parser.parseInfixExpression)
+	parser.registerInfix(token.LT_EQUAL, parser.parseInfixExpression)
+	parser.registerInfix(token.GT_EQUAL, parser.parseInfixExpression)
+	parser.registerInfix(token.AND, parser.parseInfixExpression)
+	parser.registerInfix(token.OR, parser.parseInfixExpression)
+	parser.registerInfix(token.AMPERSAND, parser.parseInfixExpression)
+	parser.registerInfix(token.PIPE, parser.parseInfixExpression)
+	parser.registerInfix(token.CARET, parser.parseInfixExpression)
+	parser.registerInfix(token.LEFT_SHIFT, parser.parseInfixExpression)
+	parser.registerInfix(token.RIGHT_SHIFT, parser.parseInfixExpression)
+	parser.registerInfix(token.ASSIGN, parser.parseAssignExpression)
+	parser.registerInfix(token.PLUS_ASSIGN, parser.parseAssignExpression)
+	parser.registerInfix(token.MINUS_ASSIGN, parser.parseAssignExpression)
+	parser.registerInfix(token.ASTERISK_ASSIGN, parser.parseAssignExpression)
+	parser.registerInfix(token.SLASH_ASSIGN, parser.parseAssignExpression)
+	parser.registerInfix(token.QUESTION, parser.parseConditionalExpression)
+	parser.registerInfix(token.OPEN_PARENTHESIS, parser.parseCallExpression)
+	parser.registerInfix(token.DOT, parser.parseMemberExpression)
+	parser.registerInfix(token.OPEN_BRACKET, parser.parseIndexExpression)
 
 	return parser
 }
@@ -95,6 +173,20 @@ func (parser *Parser) parseStatement() ast.Statement {
 		return parser.parseLetStatement()
 	case token.RETURN:
 		return parser.parseReturnStatement()
+	case token.WHILE:
+		return parser.parseWhileStatement()
+	case token.FOR:
+		return parser.parseForStatement()
+	case token.BREAK:
+		return parser.parseBreakStatement()
+	case token.CONTINUE:
+		return parser.parseContinueStatement()
+	case token.THROW:
+		return parser.parseThrowStatement()
+	case token.IMPORT:
+		return parser.parseImportStatement()
+	case token.EXPORT:
+		return parser.parseExportStatement()
 	default:
 		return parser.parseExpressionStatement()
 	}
@@ -103,18 +195,40 @@ func (parser *Parser) parseStatement() ast.Statement {
 func (parser *Parser) parseLetStatement() ast.Statement {
 	statement := &ast.LetStatement{Token: parser.currentToken}
 
-	if !parser.expectPeek(token.IDENT) {
-		return nil
-	}
+	if parser.peekTokenIs(token.OPEN_BRACKET) || parser.peekTokenIs(token.OPEN_CURLY) {
+		parser.nextToken()
+		statement.Pattern = parser.parsePattern()
+		if statement.Pattern == nil {
+			return nil
+		}
+	} else {
+		if !parser.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		statement.Name = &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal}
 
-	statement.Name = &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal}
+		var ok bool
+		if statement.Type, ok = parser.parseTypeAnnotation(token.COLON); !ok {
+			return nil
+		}
+	}
 
 	if !parser.expectPeek(token.ASSIGN) {
 		return nil
 	}
 
-	for !parser.currentTokenIs(token.SEMICOLON) {
+	parser.nextToken()
+
+	statement.Value = parser.parseExpression(LOWEST)
+
+	if function, ok := statement.Value.(*ast.FunctionLiteral); ok && statement.Name != nil {
+		function.Name = statement.Name.Value
+	}
+
+	if parser.peekTokenIs(token.SEMICOLON) {
 		parser.nextToken()
+		statement.End = parser.currentToken
 	}
 
 	return statement
@@ -123,12 +237,206 @@ func (parser *Parser) parseLetStatement() ast.Statement {
 func (parser *Parser) parseReturnStatement() ast.Statement {
 	statement := &ast.ReturnStatement{Token: parser.currentToken}
 
+	if !parser.peekTokenIs(token.SEMICOLON) && !parser.peekTokenIs(token.CLOSE_CURLY) && !parser.peekTokenIs(token.EOF) {
+		parser.nextToken()
+		statement.ReturnValue = parser.parseExpression(LOWEST)
+	}
+
+	if parser.peekTokenIs(token.SEMICOLON) {
+		parser.nextToken()
+		statement.End = parser.currentToken
+	}
+
+	return statement
+}
+
+func (parser *Parser) parseWhileStatement() ast.Statement {
+	statement := &ast.WhileStatement{Token: parser.currentToken}
+
+	if !parser.expectPeek(token.OPEN_PARENTHESIS) {
+		return nil
+	}
+
+	parser.nextToken()
+	statement.Condition = parser.parseExpression(LOWEST)
+
+	if !parser.expectPeek(token.CLOSE_PARENTHESIS) {
+		return nil
+	}
+
+	if !parser.expectPeek(token.OPEN_CURLY) {
+		return nil
+	}
+
+	statement.Body = parser.parseLoopBody()
+	return statement
+}
+
+func (parser *Parser) parseForStatement() ast.Statement {
+	forToken := parser.currentToken
+
+	if !parser.expectPeek(token.OPEN_PARENTHESIS) {
+		return nil
+	}
+
 	parser.nextToken()
 
+	if parser.currentTokenIs(token.IDENT) && parser.peekTokenIs(token.IN) {
+		return parser.parseForInStatement(forToken)
+	}
+
+	statement := &ast.ForStatement{Token: forToken}
+
 	if !parser.currentTokenIs(token.SEMICOLON) {
+		if parser.currentTokenIs(token.LET) {
+			statement.Init = parser.parseLetStatement()
+		} else {
+			statement.Init = parser.parseExpressionStatement()
+		}
+
+		if !parser.currentTokenIs(token.SEMICOLON) && !parser.expectPeek(token.SEMICOLON) {
+			return nil
+		}
+	}
+
+	if !parser.peekTokenIs(token.SEMICOLON) {
+		parser.nextToken()
+		statement.Condition = parser.parseExpression(LOWEST)
+	}
+
+	if !parser.expectPeek(token.SEMICOLON) {
+		return nil
+	}
+
+	if !parser.peekTokenIs(token.CLOSE_PARENTHESIS) {
 		parser.nextToken()
+		statement.Update = parser.parseExpression(LOWEST)
+	}
+
+	if !parser.expectPeek(token.CLOSE_PARENTHESIS) {
+		return nil
 	}
 
+	if !parser.expectPeek(token.OPEN_CURLY) {
+		return nil
+	}
+
+	statement.Body = parser.parseLoopBody()
+	return statement
+}
+
+func (parser *Parser) parseForInStatement(forToken token.Token) ast.Statement {
+	statement := &ast.ForInStatement{Token: forToken}
+	statement.Variable = &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal}
+
+	parser.nextToken()
+	parser.nextToken()
+	statement.Iterable = parser.parseExpression(LOWEST)
+
+	if !parser.expectPeek(token.CLOSE_PARENTHESIS) {
+		return nil
+	}
+
+	if !parser.expectPeek(token.OPEN_CURLY) {
+		return nil
+	}
+
+	statement.Body = parser.parseLoopBody()
+	return statement
+}
+
+func (parser *Parser) parseLoopBody() *ast.BlockStatement {
+	parser.loopDepth++
+	defer func() { parser.loopDepth-- }()
+
+	return parser.parseBlockStatement()
+}
+
+func (parser *Parser) parseBreakStatement() ast.Statement {
+	statement := &ast.BreakStatement{Token: parser.currentToken}
+
+	if parser.loopDepth == 0 {
+		parser.addError(parser.currentToken, "break statement outside of a loop")
+	}
+
+	if parser.peekTokenIs(token.SEMICOLON) {
+		parser.nextToken()
+		statement.End = parser.currentToken
+	}
+
+	return statement
+}
+
+func (parser *Parser) parseContinueStatement() ast.Statement {
+	statement := &ast.ContinueStatement{Token: parser.currentToken}
+
+	if parser.loopDepth == 0 {
+		parser.addError(parser.currentToken, "continue statement outside of a loop")
+	}
+
+	if parser.peekTokenIs(token.SEMICOLON) {
+		parser.nextToken()
+		statement.End = parser.currentToken
+	}
+
+	return statement
+}
+
+func (parser *Parser) parseThrowStatement() ast.Statement {
+	statement := &ast.ThrowStatement{Token: parser.currentToken}
+
+	parser.nextToken()
+
+	statement.Value = parser.parseExpression(LOWEST)
+
+	if parser.peekTokenIs(token.SEMICOLON) {
+		parser.nextToken()
+		statement.End = parser.currentToken
+	}
+
+	return statement
+}
+
+func (parser *Parser) parseImportStatement() ast.Statement {
+	statement := &ast.ImportStatement{Token: parser.currentToken}
+
+	if !parser.expectPeek(token.STRING) {
+		return nil
+	}
+
+	statement.Path = &ast.StringLiteral{Token: parser.currentToken, Value: parser.currentToken.Literal}
+
+	if !parser.expectPeek(token.AS) {
+		return nil
+	}
+
+	if !parser.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	statement.Alias = &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal}
+
+	if parser.peekTokenIs(token.SEMICOLON) {
+		parser.nextToken()
+		statement.End = parser.currentToken
+	}
+
+	return statement
+}
+
+func (parser *Parser) parseExportStatement() ast.Statement {
+	statement := &ast.ExportStatement{Token: parser.currentToken}
+
+	if !parser.expectPeek(token.LET) {
+		return nil
+	}
+
+	let, ok := parser.parseLetStatement().(*ast.LetStatement)
+	if !ok {
+		return nil
+	}
+
+	statement.Statement = let
 	return statement
 }
 
@@ -139,12 +447,15 @@ func (parser *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 
 	if parser.peekTokenIs(token.SEMICOLON) {
 		parser.nextToken()
+		statement.End = parser.currentToken
 	}
 
 	return statement
 }
 
 func (parser *Parser) parseExpression(precedence int) ast.Expression {
+	defer parser.untrace(parser.trace("parseExpression"))
+
 	prefix := parser.prefixParseFns[parser.currentToken.Type]
 
 	if prefix == nil {
@@ -176,17 +487,29 @@ func (parser *Parser) parseIntegerLiteral() ast.Expression {
 	lit := &ast.IntegerLiteral{Token: parser.currentToken}
 
 	value, err := strconv.ParseInt(parser.currentToken.Literal, 0, 64)
-	if err != nil {
+	if err == nil {
+		lit.Value = value
+		return lit
+	}
+
+	bigValue, ok := new(big.Int).SetString(parser.currentToken.Literal, 0)
+	if !ok {
 		msg := fmt.Sprintf("could not parse %q as integer", parser.currentToken.Literal)
-		parser.errors = append(parser.errors, msg)
+		parser.addError(parser.currentToken, msg)
 		return nil
 	}
 
-	lit.Value = value
+	lit.Big = bigValue
 	return lit
 }
 
+func (parser *Parser) parseStringLiteral() ast.Expression {
+	return &ast.StringLiteral{Token: parser.currentToken, Value: parser.currentToken.Literal}
+}
+
 func (parser *Parser) parsePrefixExpression() ast.Expression {
+	defer parser.untrace(parser.trace("parsePrefixExpression"))
+
 	expression := &ast.PrefixExpression{
 		Token:    parser.currentToken,
 		Operator: parser.currentToken.Literal,
@@ -199,32 +522,72 @@ func (parser *Parser) parsePrefixExpression() ast.Expression {
 }
 
 func (parser *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
+	defer parser.untrace(parser.trace("parseInfixExpression"))
+
 	expression := &ast.InfixExpression{
 		Token:    parser.currentToken,
 		Operator: parser.currentToken.Literal,
 		Left:     left,
 	}
 
-	precedence := parser.currentPrecedence()
+	precedence := parser.currentOperandPrecedence()
 	parser.nextToken()
 	expression.Right = parser.parseExpression(precedence)
 
 	return expression
 }
 
+func (parser *Parser) parseAssignExpression(target ast.Expression) ast.Expression {
+	expression := &ast.AssignExpression{
+		Token:    parser.currentToken,
+		Operator: parser.currentToken.Literal,
+		Target:   target,
+	}
+
+	if target == nil {
+		return nil
+	}
+
+	target = ast.Unparen(target)
+	expression.Target = target
+
+	switch target.(type) {
+	case *ast.Identifier, *ast.IndexExpression:
+	default:
+		msg := fmt.Sprintf("cannot assign to %s", target.String())
+		parser.addError(parser.currentToken, msg)
+		return nil
+	}
+
+	precedence := parser.currentOperandPrecedence()
+	parser.nextToken()
+	expression.Value = parser.parseExpression(precedence)
+	if expression.Value == nil {
+		return nil
+	}
+
+	return expression
+}
+
 func (parser *Parser) parseBoolean() ast.Expression {
 	return &ast.Boolean{Token: parser.currentToken, Value: parser.currentTokenIs(token.TRUE)}
 }
 
 func (parser *Parser) parseGroupExpressions() ast.Expression {
+	expression := &ast.GroupedExpression{Token: parser.currentToken}
+
 	parser.nextToken()
 
-	expression := parser.parseExpression(LOWEST)
+	expression.Expression = parser.parseExpression(LOWEST)
+	if expression.Expression == nil {
+		return nil
+	}
 
 	if !parser.expectPeek(token.CLOSE_PARENTHESIS) {
 		return nil
 	}
 
+	expression.End = parser.currentToken
 	return expression
 }
 
@@ -251,6 +614,15 @@ func (parser *Parser) parseIfExpression() ast.Expression {
 	if parser.peekTokenIs(token.ELSE) {
 		parser.nextToken()
 
+		if parser.peekTokenIs(token.IF) {
+			parser.nextToken()
+			expression.Alternative = parser.parseElseIf()
+			if expression.Alternative == nil {
+				return nil
+			}
+			return expression
+		}
+
 		if !parser.expectPeek(token.OPEN_CURLY) {
 			return nil
 		}
@@ -260,6 +632,47 @@ func (parser *Parser) parseIfExpression() ast.Expression {
 	return expression
 }
 
+func (parser *Parser) parseElseIf() *ast.BlockStatement {
+	ifToken := parser.currentToken
+
+	nested := parser.parseIfExpression()
+	if nested == nil {
+		return nil
+	}
+
+	return &ast.BlockStatement{
+		Token:      ifToken,
+		Statements: []ast.Statement{&ast.ExpressionStatement{Token: ifToken, Expression: nested}},
+	}
+}
+
+func (parser *Parser) parseConditionalExpression(condition ast.Expression) ast.Expression {
+	expression := &ast.ConditionalExpression{
+		Token:     parser.currentToken,
+		Condition: condition,
+	}
+
+	precedence := parser.currentOperandPrecedence()
+
+	parser.nextToken()
+	expression.Consequence = parser.parseExpression(LOWEST)
+	if expression.Consequence == nil {
+		return nil
+	}
+
+	if !parser.expectPeek(token.COLON) {
+		return nil
+	}
+
+	parser.nextToken()
+	expression.Alternative = parser.parseExpression(precedence)
+	if expression.Alternative == nil {
+		return nil
+	}
+
+	return expression
+}
+
 func (parser *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{Token: parser.currentToken}
 	block.Statements = []ast.Statement{}
@@ -268,6 +681,11 @@ func (parser *Parser) parseBlockStatement() *ast.BlockStatement {
 
 	for !parser.currentTokenIs(token.CLOSE_CURLY) && !parser.currentTokenIs(token.EOF) {
 
+		if parser.currentTokenIs(token.IMPORT) || parser.currentTokenIs(token.EXPORT) {
+			msg := fmt.Sprintf("%s statements are only allowed at the top level", parser.currentToken.Literal)
+			parser.addError(parser.currentToken, msg)
+		}
+
 		statement := parser.parseStatement()
 		if statement != nil {
 			block.Statements = append(block.Statements, statement)
@@ -276,9 +694,128 @@ func (parser *Parser) parseBlockStatement() *ast.BlockStatement {
 		parser.nextToken()
 	}
 
+	if parser.currentTokenIs(token.CLOSE_CURLY) {
+		block.End = parser.currentToken
+	}
+
 	return block
 }
 
+func (parser *Parser) parseMatchExpression() ast.Expression {
+	expression := &ast.MatchExpression{Token: parser.currentToken}
+
+	if !parser.expectPeek(token.OPEN_PARENTHESIS) {
+		return nil
+	}
+
+	parser.nextToken()
+	expression.Subject = parser.parseExpression(LOWEST)
+
+	if !parser.expectPeek(token.CLOSE_PARENTHESIS) {
+		return nil
+	}
+
+	if !parser.expectPeek(token.OPEN_CURLY) {
+		return nil
+	}
+
+	expression.Arms = []*ast.MatchArm{}
+
+	for !parser.peekTokenIs(token.CLOSE_CURLY) {
+		parser.nextToken()
+
+		arm := parser.parseMatchArm()
+		if arm == nil {
+			return nil
+		}
+		expression.Arms = append(expression.Arms, arm)
+
+		if !parser.peekTokenIs(token.CLOSE_CURLY) && !parser.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	parser.nextToken()
+	expression.End = parser.currentToken
+
+	return expression
+}
+
+func (parser *Parser) parseMatchArm() *ast.MatchArm {
+	arm := &ast.MatchArm{Token: parser.currentToken}
+
+	arm.Pattern = parser.parsePattern()
+	if arm.Pattern == nil {
+		return nil
+	}
+
+	if parser.peekTokenIs(token.IF) {
+		parser.nextToken()
+		parser.nextToken()
+		arm.Guard = parser.parseExpression(LOWEST)
+	}
+
+	if !parser.expectPeek(token.FAT_ARROW) {
+		return nil
+	}
+
+	parser.nextToken()
+	arm.Body = parser.parseExpression(LOWEST)
+
+	return arm
+}
+
+func (parser *Parser) parseTryExpression() ast.Expression {
+	expression := &ast.TryExpression{Token: parser.currentToken}
+
+	if !parser.expectPeek(token.OPEN_CURLY) {
+		return nil
+	}
+
+	expression.Block = parser.parseBlockStatement()
+
+	if parser.peekTokenIs(token.CATCH) {
+		parser.nextToken()
+
+		if !parser.expectPeek(token.OPEN_PARENTHESIS) {
+			return nil
+		}
+
+		if !parser.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		expression.CatchParameter = &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal}
+
+		if !parser.expectPeek(token.CLOSE_PARENTHESIS) {
+			return nil
+		}
+
+		if !parser.expectPeek(token.OPEN_CURLY) {
+			return nil
+		}
+
+		expression.Catch = parser.parseBlockStatement()
+	}
+
+	if parser.peekTokenIs(token.FINALLY) {
+		parser.nextToken()
+
+		if !parser.expectPeek(token.OPEN_CURLY) {
+			return nil
+		}
+
+		expression.Finally = parser.parseBlockStatement()
+	}
+
+	if expression.Catch == nil && expression.Finally == nil {
+		parser.addError(parser.currentToken, "try expression requires a catch or finally block")
+		return nil
+	}
+
+	return expression
+}
+
 func (parser *Parser) parseFunctionLiteral() ast.Expression {
 	literal := &ast.FunctionLiteral{Token: parser.currentToken}
 
@@ -288,41 +825,192 @@ func (parser *Parser) parseFunctionLiteral() ast.Expression {
 
 	literal.Parameters = parser.parseFunctionParameters()
 
+	var ok bool
+	if literal.ReturnType, ok = parser.parseTypeAnnotation(token.ARROW); !ok {
+		return nil
+	}
+
 	if !parser.expectPeek(token.OPEN_CURLY) {
 		return nil
 	}
 
+	loopDepth := parser.loopDepth
+	parser.loopDepth = 0
 	literal.Body = parser.parseBlockStatement()
+	parser.loopDepth = loopDepth
 
 	return literal
 }
 
-func (parser *Parser) parseFunctionParameters() []*ast.Identifier {
-	identifiers := []*ast.Identifier{}
+func (parser *Parser) parseFunctionParameters() []ast.Pattern {
+	parameters := []ast.Pattern{}
 
 	if parser.peekTokenIs(token.CLOSE_PARENTHESIS) {
 		parser.nextToken()
-		return identifiers
+		return parameters
 	}
 
 	parser.nextToken()
 
-	ident := &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal}
-	identifiers = append(identifiers, ident)
+	parameter := parser.parseParameter()
+	if parameter == nil {
+		return nil
+	}
+	parameters = append(parameters, parameter)
 
 	for parser.peekTokenIs(token.COMMA) {
+		if _, ok := parameter.(*ast.RestPattern); ok {
+			parser.addError(parser.currentToken, "rest parameter must be last")
+			return nil
+		}
+
 		parser.nextToken()
 		parser.nextToken()
 
-		ident := &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal}
-		identifiers = append(identifiers, ident)
+		parameter = parser.parseParameter()
+		if parameter == nil {
+			return nil
+		}
+		parameters = append(parameters, parameter)
 	}
 
 	if !parser.expectPeek(token.CLOSE_PARENTHESIS) {
 		return nil
 	}
 
-	return identifiers
+	return parameters
+}
+
+func (parser *Parser) parseParameter() ast.Pattern {
+	switch parser.currentToken.Type {
+	case token.ELLIPSIS:
+		return parser.parseRestPattern()
+	case token.IDENT:
+		parameter := &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal}
+
+		var ok bool
+		if parameter.Type, ok = parser.parseTypeAnnotation(token.COLON); !ok {
+			return nil
+		}
+
+		return parser.parseDefault(parameter)
+	case token.OPEN_BRACKET, token.OPEN_CURLY:
+		return parser.parsePatternWithDefault()
+	default:
+		msg := fmt.Sprintf("expected parameter name or pattern, got %s instead", parser.currentToken.Type)
+		parser.addError(parser.currentToken, msg)
+		return nil
+	}
+}
+
+func (parser *Parser) parseCallExpression(function ast.Expression) ast.Expression {
+	expression := &ast.CallExpression{Token: parser.currentToken, Function: function}
+	expression.Arguments = parser.parseCallArguments()
+	if expression.Arguments == nil {
+		return nil
+	}
+
+	expression.End = parser.currentToken
+	return expression
+}
+
+func (parser *Parser) parseCallArguments() []ast.Expression {
+	args := []ast.Expression{}
+
+	if parser.peekTokenIs(token.CLOSE_PARENTHESIS) {
+		parser.nextToken()
+		return args
+	}
+
+	named := map[string]bool{}
+
+	for {
+		parser.nextToken()
+
+		arg := parser.parseCallArgument()
+		if arg == nil {
+			return nil
+		}
+
+		if namedArg, ok := arg.(*ast.NamedArgument); ok {
+			if named[namedArg.Name.Value] {
+				msg := fmt.Sprintf("duplicate named argument %s", namedArg.Name.Value)
+				parser.addError(parser.currentToken, msg)
+				return nil
+			}
+			named[namedArg.Name.Value] = true
+		} else if len(named) > 0 {
+			parser.addError(parser.currentToken, "positional argument after named argument")
+			return nil
+		}
+
+		args = append(args, arg)
+
+		if !parser.peekTokenIs(token.COMMA) {
+			break
+		}
+		parser.nextToken()
+	}
+
+	if !parser.expectPeek(token.CLOSE_PARENTHESIS) {
+		return nil
+	}
+
+	return args
+}
+
+func (parser *Parser) parseCallArgument() ast.Expression {
+	if parser.currentTokenIs(token.ELLIPSIS) {
+		spread := &ast.SpreadExpression{Token: parser.currentToken}
+
+		parser.nextToken()
+		spread.Value = parser.parseExpression(LOWEST)
+		if spread.Value == nil {
+			return nil
+		}
+
+		return spread
+	}
+
+	if parser.currentTokenIs(token.IDENT) && parser.peekTokenIs(token.COLON) {
+		named := &ast.NamedArgument{Token: parser.currentToken}
+		named.Name = &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal}
+
+		parser.nextToken()
+		parser.nextToken()
+		named.Value = parser.parseExpression(LOWEST)
+		if named.Value == nil {
+			return nil
+		}
+
+		return named
+	}
+
+	return parser.parseExpression(LOWEST)
+}
+
+func (parser *Parser) parseMemberExpression(object ast.Expression) ast.Expression {
+	expression := &ast.MemberExpression{Token: parser.currentToken, Object: object}
+
+	if !parser.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	expression.Property = &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal}
+	return expression
+}
+
+func (parser *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	expression := &ast.IndexExpression{Token: parser.currentToken, Left: left}
+
+	parser.nextToken()
+	expression.Index = parser.parseExpression(LOWEST)
+	if expression.Index == nil || !parser.expectPeek(token.CLOSE_BRACKET) {
+		return nil
+	}
+
+	expression.End = parser.currentToken
+	return expression
 }
 
 type (
@@ -357,17 +1045,29 @@ func (parser *Parser) expectPeek(tokenType token.TokenType) bool {
 }
 
 func (parser *Parser) Errors() []string {
+	messages := []string{}
+	for _, err := range parser.errors {
+		messages = append(messages, err.Message)
+	}
+	return messages
+}
+
+func (parser *Parser) PositionedErrors() []Error {
 	return parser.errors
 }
 
+func (parser *Parser) addError(tok token.Token, msg string) {
+	parser.errors = append(parser.errors, Error{Message: msg, Line: tok.Line, Column: tok.Column})
+}
+
 func (parser *Parser) peekError(tokenType token.TokenType) {
 	msg := fmt.Sprintf("expected next token to be %s, got %s instead", tokenType, parser.peekToken.Type)
-	parser.errors = append(parser.errors, msg)
+	parser.addError(parser.peekToken, msg)
 }
 
 func (parser *Parser) noPrefixParseFnError(tokenType token.TokenType) {
 	msg := fmt.Sprintf("no prefix parse function for %s found", tokenType)
-	parser.errors = append(parser.errors, msg)
+	parser.addError(parser.currentToken, msg)
 }
 
 func (parser *Parser) peekPrecedence() int {
@@ -385,3 +1085,12 @@ func (parser *Parser) currentPrecedence() int {
 
 	return LOWEST
 }
+
+func (parser *Parser) currentOperandPrecedence() int {
+	precedence := parser.currentPrecedence()
+	if rightAssociative[parser.currentToken.Type] {
+		precedence--
+	}
+
+	return precedence
+}
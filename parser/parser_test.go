@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"gomonkey/ast"
 	"gomonkey/lexer"
+	"strings"
 	"testing"
 )
 
@@ -76,6 +77,34 @@ func testLetStatement(t *testing.T, statement ast.Statement, name string) bool {
 	return true
 }
 
+func TestLetStatementValues(t *testing.T) {
+	tests := []struct {
+		input              string
+		expectedIdentifier string
+		expectedValue      interface{}
+	}{
+		{"let x = 5;", "x", 5},
+		{"let y = true;", "y", true},
+		{"let foobar = y", "foobar", "y"},
+	}
+
+	for _, test := range tests {
+		program := create(t, test.input)
+
+		checkStatementLength(t, program.Statements, 1)
+
+		statement := program.Statements[0]
+		if !testLetStatement(t, statement, test.expectedIdentifier) {
+			return
+		}
+
+		value := statement.(*ast.LetStatement).Value
+		if !testLiteralExpression(t, value, test.expectedValue) {
+			return
+		}
+	}
+}
+
 func TestReturnStatements(t *testing.T) {
 	input := `return 5;
 						return 10;
@@ -98,6 +127,65 @@ func TestReturnStatements(t *testing.T) {
 	}
 }
 
+func TestReturnStatementValues(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedValue interface{}
+	}{
+		{"return 5;", 5},
+		{"return true;", true},
+		{"return foobar", "foobar"},
+	}
+
+	for _, test := range tests {
+		program := create(t, test.input)
+
+		checkStatementLength(t, program.Statements, 1)
+
+		returnStatement, ok := program.Statements[0].(*ast.ReturnStatement)
+		if !ok {
+			t.Fatalf("statement not *ast.ReturnStatement. got=%T", program.Statements[0])
+		}
+		if !testLiteralExpression(t, returnStatement.ReturnValue, test.expectedValue) {
+			return
+		}
+	}
+}
+
+func TestBareReturnStatements(t *testing.T) {
+	tests := []string{
+		"return;",
+		"return",
+		"fn() { return }",
+		"fn() { return; }",
+		"if (x) { return } else { return; }",
+	}
+
+	for _, input := range tests {
+		program := create(t, input)
+
+		checkStatementLength(t, program.Statements, 1)
+
+		returns := 0
+		ast.Inspect(program, func(node ast.Node) bool {
+			if statement, ok := node.(*ast.ReturnStatement); ok {
+				returns++
+				if statement.ReturnValue != nil {
+					t.Errorf("ReturnValue not nil for %q. got=%s", input, statement.ReturnValue)
+				}
+				if statement.String() != "return ;" {
+					t.Errorf("statement.String() wrong for %q. got=%q", input, statement.String())
+				}
+			}
+			return true
+		})
+
+		if returns == 0 {
+			t.Errorf("no return statement found in %q", input)
+		}
+	}
+}
+
 func checkParserErrors(t *testing.T, parser *Parser) {
 	errors := parser.Errors()
 
@@ -160,6 +248,71 @@ func TestIntegerLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestBigIntegerLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input       string
+		expectedBig string
+	}{
+		{"9223372036854775807;", ""},
+		{"9223372036854775808;", "9223372036854775808"},
+		{"123456789012345678901234567890;", "123456789012345678901234567890"},
+	}
+
+	for _, test := range tests {
+		program := create(t, test.input)
+
+		checkStatementLength(t, program.Statements, 1)
+
+		statement, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+		}
+
+		literal, ok := statement.Expression.(*ast.IntegerLiteral)
+		if !ok {
+			t.Fatalf("expression is not *ast.IntegerLiteral. got=%T", statement.Expression)
+		}
+
+		if test.expectedBig == "" {
+			if literal.Big != nil {
+				t.Errorf("literal.Big not nil for %s. got=%s", test.input, literal.Big)
+			}
+			continue
+		}
+
+		if literal.Big == nil {
+			t.Fatalf("literal.Big is nil for %s", test.input)
+		}
+		if literal.Big.String() != test.expectedBig {
+			t.Errorf("literal.Big not %s. got=%s", test.expectedBig, literal.Big)
+		}
+		if literal.String() != test.expectedBig {
+			t.Errorf("literal.String() not %s. got=%s", test.expectedBig, literal.String())
+		}
+	}
+}
+
+func TestStringLiteralExpression(t *testing.T) {
+	input := `"hello world";`
+
+	program := create(t, input)
+
+	checkStatementLength(t, program.Statements, 1)
+
+	statement, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	literal, ok := statement.Expression.(*ast.StringLiteral)
+	if !ok {
+		t.Fatalf("expression is not *ast.StringLiteral. got=%T", statement.Expression)
+	}
+	if literal.Value != "hello world" {
+		t.Errorf("literal.Value not %q. got=%q", "hello world", literal.Value)
+	}
+}
+
 func TestBooleanExpression(t *testing.T) {
 	input := "true;"
 
@@ -194,6 +347,7 @@ func TestParsingPrefixExpressions(t *testing.T) {
 		{"-15;", "-", 15},
 		{"!true;", "!", true},
 		{"!false;", "!", false},
+		{"~5;", "~", 5},
 	}
 
 	for _, test := range prefixTests {
@@ -255,6 +409,17 @@ func TestParsingInfixExpressions(t *testing.T) {
 		{"5 < 5;", 5, "<", 5},
 		{"5 == 5;", 5, "==", 5},
 		{"5 != 5;", 5, "!=", 5},
+		{"5 <= 5;", 5, "<=", 5},
+		{"5 >= 5;", 5, ">=", 5},
+		{"5 % 5;", 5, "%", 5},
+		{"5 ** 5;", 5, "**", 5},
+		{"5 & 5;", 5, "&", 5},
+		{"5 | 5;", 5, "|", 5},
+		{"5 ^ 5;", 5, "^", 5},
+		{"5 << 5;", 5, "<<", 5},
+		{"5 >> 5;", 5, ">>", 5},
+		{"true && false", true, "&&", false},
+		{"true || false", true, "||", false},
 		{"true == true", true, "==", true},
 		{"true != false", true, "!=", false},
 		{"false == false", false, "==", false},
@@ -381,6 +546,162 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"!(true == true)",
 			"(!(true == true))",
 		},
+		{
+			"a <= b == b >= a",
+			"((a <= b) == (b >= a))",
+		},
+		{
+			"a + b % c * d",
+			"(a + ((b % c) * d))",
+		},
+		{
+			"a || b && c",
+			"(a || (b && c))",
+		},
+		{
+			"a && b || c && d",
+			"((a && b) || (c && d))",
+		},
+		{
+			"a == b && c != d",
+			"((a == b) && (c != d))",
+		},
+		{
+			"a < b || !c",
+			"((a < b) || (!c))",
+		},
+		{
+			"a | b ^ c & d",
+			"(a | (b ^ (c & d)))",
+		},
+		{
+			"a & b == c",
+			"(a & (b == c))",
+		},
+		{
+			"a << b + c",
+			"(a << (b + c))",
+		},
+		{
+			"a < b << c",
+			"(a < (b << c))",
+		},
+		{
+			"a >> b >> c",
+			"((a >> b) >> c)",
+		},
+		{
+			"a && b | c",
+			"(a && (b | c))",
+		},
+		{
+			"~a & b",
+			"((~a) & b)",
+		},
+		{
+			"2 ** 3 ** 2",
+			"(2 ** (3 ** 2))",
+		},
+		{
+			"a * b ** c",
+			"(a * (b ** c))",
+		},
+		{
+			"a ** b * c",
+			"((a ** b) * c)",
+		},
+		{
+			"-a ** b",
+			"(-(a ** b))",
+		},
+		{
+			"a ** -b",
+			"(a ** (-b))",
+		},
+		{
+			"(a ** b) ** c",
+			"((a ** b) ** c)",
+		},
+		{
+			"a - b - c ** d ** e",
+			"((a - b) - (c ** (d ** e)))",
+		},
+		{
+			"a = b = c",
+			"(a = (b = c))",
+		},
+		{
+			"a += b * c",
+			"(a += (b * c))",
+		},
+		{
+			"a = b || c",
+			"(a = (b || c))",
+		},
+		{
+			"a -= b *= c",
+			"(a -= (b *= c))",
+		},
+		{
+			"a ? b : c",
+			"(a ? b : c)",
+		},
+		{
+			"a ? b : c ? d : e",
+			"(a ? b : (c ? d : e))",
+		},
+		{
+			"a ? b ? c : d : e",
+			"(a ? (b ? c : d) : e)",
+		},
+		{
+			"a || b ? c + d : e * f",
+			"((a || b) ? (c + d) : (e * f))",
+		},
+		{
+			"x = a ? b : c",
+			"(x = (a ? b : c))",
+		},
+		{
+			"(x) = 1",
+			"(x = 1)",
+		},
+		{
+			"((x)) += 1",
+			"(x += 1)",
+		},
+		{
+			"a + add(b * c) + d",
+			"((a + add((b * c))) + d)",
+		},
+		{
+			"add(a, b, 1, 2 * 3, 4 + 5, add(6, 7 * 8))",
+			"add(a, b, 1, (2 * 3), (4 + 5), add(6, (7 * 8)))",
+		},
+		{
+			"add(a + b + c * d / f + g)",
+			"add((((a + b) + ((c * d) / f)) + g))",
+		},
+		{
+			"-f(x) ** 2",
+			"(-(f(x) ** 2))",
+		},
+		{
+			"a.b.c(d) + e.f",
+			"(a.b.c(d) + e.f)",
+		},
+		{
+			"a * b[1] * c",
+			"((a * (b[1])) * c)",
+		},
+		{
+			"add(a * b[2], c[1], 2 * f(x)[0])",
+			"add((a * (b[2])), (c[1]), (2 * (f(x)[0])))",
+		},
+		{
+			"a[0] = b[i] = 1",
+			"((a[0]) = ((b[i]) = 1))",
+		},
 	}
 
 	for _, test := range tests {
@@ -549,6 +870,133 @@ func TestIfElseExpression(t *testing.T) {
 
 }
 
+func TestElseIfExpression(t *testing.T) {
+	input := `if (x < y) { x } else if (x > y) { y } else { z }`
+
+	program := create(t, input)
+
+	checkStatementLength(t, program.Statements, 1)
+
+	statement, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+	exp, ok := statement.Expression.(*ast.IfExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.IfExpression. got=%T", statement.Expression)
+	}
+	if !testInfixExpression(t, exp.Condition, "x", "<", "y") {
+		return
+	}
+
+	checkStatementLength(t, exp.Alternative.Statements, 1)
+
+	alternative, ok := exp.Alternative.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("Alternative.Statements[0] is not *ast.ExpressionStatement. got=%T",
+			exp.Alternative.Statements[0])
+	}
+	nested, ok := alternative.Expression.(*ast.IfExpression)
+	if !ok {
+		t.Fatalf("alternative.Expression is not ast.IfExpression. got=%T", alternative.Expression)
+	}
+	if !testInfixExpression(t, nested.Condition, "x", ">", "y") {
+		return
+	}
+
+	consequence, ok := nested.Consequence.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("nested.Consequence.Statements[0] is not ast.ExpressionStatement. got=%T",
+			nested.Consequence.Statements[0])
+	}
+	if !testIdentifier(t, consequence.Expression, "y") {
+		return
+	}
+
+	if nested.Alternative == nil {
+		t.Fatalf("nested.Alternative is nil")
+	}
+	last, ok := nested.Alternative.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("nested.Alternative.Statements[0] is not ast.ExpressionStatement. got=%T",
+			nested.Alternative.Statements[0])
+	}
+	if !testIdentifier(t, last.Expression, "z") {
+		return
+	}
+}
+
+func TestElseIfMatchesNestedIf(t *testing.T) {
+	elseIf := create(t, `if (a) { x } else if (b) { y } else { z }`)
+	nested := create(t, `if (a) { x } else { if (b) { y } else { z } }`)
+
+	elseIfAlternative := elseIf.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IfExpression).Alternative
+	nestedAlternative := nested.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IfExpression).Alternative
+
+	if diff := ast.Diff(elseIfAlternative.Statements[0], nestedAlternative.Statements[0]); diff != "" {
+		t.Errorf("else if does not match nested if: %s", diff)
+	}
+
+	different := create(t, `if (a) { x } else { if (b) { w } else { z } }`)
+	expected := `Statements[0].Expression.Alternative.Statements[0].Expression.Consequence.Statements[0].Expression.Value: "y" != "w"`
+
+	if diff := ast.Diff(nested, different); diff != expected {
+		t.Errorf("diff wrong.\nexpected=%s\ngot=%s", expected, diff)
+	}
+}
+
+func TestConditionalExpression(t *testing.T) {
+	input := `x > 0 ? x : y`
+
+	program := create(t, input)
+
+	checkStatementLength(t, program.Statements, 1)
+
+	statement, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+	exp, ok := statement.Expression.(*ast.ConditionalExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.ConditionalExpression. got=%T", statement.Expression)
+	}
+	if !testInfixExpression(t, exp.Condition, "x", ">", 0) {
+		return
+	}
+	if !testIdentifier(t, exp.Consequence, "x") {
+		return
+	}
+	if !testIdentifier(t, exp.Alternative, "y") {
+		return
+	}
+}
+
+func TestConditionalsInLetStatements(t *testing.T) {
+	input := `let a = if (x) { 1 } else if (y) { 2 } else { 3 };
+let b = x ? 1 : y ? 2 : 3;`
+
+	program := create(t, input)
+
+	checkStatementLength(t, program.Statements, 2)
+
+	if !testLetStatement(t, program.Statements[0], "a") {
+		return
+	}
+	if _, ok := program.Statements[0].(*ast.LetStatement).Value.(*ast.IfExpression); !ok {
+		t.Errorf("a is not bound to ast.IfExpression. got=%T", program.Statements[0].(*ast.LetStatement).Value)
+	}
+
+	if !testLetStatement(t, program.Statements[1], "b") {
+		return
+	}
+	value := program.Statements[1].(*ast.LetStatement).Value
+	if value.String() != "(x ? 1 : (y ? 2 : 3))" {
+		t.Errorf("b value wrong. got=%q", value.String())
+	}
+}
+
 func TestFunctionLiteral(t *testing.T) {
 	input := `fn(x, y) { x + y; }`
 
@@ -571,8 +1019,8 @@ func TestFunctionLiteral(t *testing.T) {
 			len(function.Parameters))
 	}
 
-	testLiteralExpression(t, function.Parameters[0], "x")
-	testLiteralExpression(t, function.Parameters[1], "y")
+	testParameter(t, function.Parameters[0], "x")
+	testParameter(t, function.Parameters[1], "y")
 
 	checkStatementLength(t, function.Body.Statements, 1)
 
@@ -606,7 +1054,1086 @@ func TestFunctionParameterParsing(t *testing.T) {
 		}
 
 		for i, ident := range test.exptectedParams {
-			testLiteralExpression(t, function.Parameters[i], ident)
+			testParameter(t, function.Parameters[i], ident)
 		}
 	}
 }
+
+func testParameter(t *testing.T, parameter ast.Pattern, name string) bool {
+	ident, ok := parameter.(*ast.Identifier)
+	if !ok {
+		t.Errorf("parameter not *ast.Identifier. got=%T", parameter)
+		return false
+	}
+
+	return testIdentifier(t, ident, name)
+}
+
+func TestAssignExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		target   string
+		operator string
+		value    interface{}
+	}{
+		{"x = 5;", "x", "=", 5},
+		{"x += 1;", "x", "+=", 1},
+		{"x -= y;", "x", "-=", "y"},
+		{"x *= 2;", "x", "*=", 2},
+		{"x /= true;", "x", "/=", true},
+	}
+
+	for _, test := range tests {
+		program := create(t, test.input)
+
+		checkStatementLength(t, program.Statements, 1)
+
+		statement, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+				program.Statements[0])
+		}
+
+		assign, ok := statement.Expression.(*ast.AssignExpression)
+		if !ok {
+			t.Fatalf("expression is not ast.AssignExpression. got=%T", statement.Expression)
+		}
+		if !testIdentifier(t, assign.Target, test.target) {
+			return
+		}
+		if assign.Operator != test.operator {
+			t.Fatalf("assign.Operator is not '%s'. got=%s", test.operator, assign.Operator)
+		}
+		if !testLiteralExpression(t, assign.Value, test.value) {
+			return
+		}
+	}
+}
+
+func TestAssignToIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		left     string
+		index    string
+		operator string
+		value    interface{}
+	}{
+		{"arr[0] = 5;", "arr", "0", "=", 5},
+		{"h[\"k\"] = v;", "h", "\"k\"", "=", "v"},
+		{"arr[i] += 1;", "arr", "i", "+=", 1},
+	}
+
+	for _, test := range tests {
+		program := create(t, test.input)
+
+		checkStatementLength(t, program.Statements, 1)
+
+		statement, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+				program.Statements[0])
+		}
+
+		assign, ok := statement.Expression.(*ast.AssignExpression)
+		if !ok {
+			t.Fatalf("expression is not ast.AssignExpression. got=%T", statement.Expression)
+		}
+
+		index, ok := assign.Target.(*ast.IndexExpression)
+		if !ok {
+			t.Fatalf("assign.Target is not ast.IndexExpression. got=%T", assign.Target)
+		}
+		if !testIdentifier(t, index.Left, test.left) {
+			return
+		}
+		if index.Index.String() != test.index {
+			t.Fatalf("index.Index is not %s. got=%s", test.index, index.Index)
+		}
+		if assign.Operator != test.operator {
+			t.Fatalf("assign.Operator is not '%s'. got=%s", test.operator, assign.Operator)
+		}
+		if !testLiteralExpression(t, assign.Value, test.value) {
+			return
+		}
+	}
+}
+
+func TestInvalidAssignTargets(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"5 = x;", "cannot assign to 5"},
+		{"a + b = c;", "cannot assign to (a + b)"},
+		{"a == b += c;", "cannot assign to (a == b)"},
+	}
+
+	for _, test := range tests {
+		parser := New(lexer.New(test.input))
+		parser.ParseProgram()
+
+		errors := parser.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected parser errors for %q, got none", test.input)
+			continue
+		}
+		if errors[0] != test.expectedError {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", test.input, test.expectedError, errors[0])
+		}
+	}
+}
+
+func TestIncompleteExpressionsDoNotPanic(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"x +=", "no prefix parse function for EOF found"},
+		{"x = ;", "no prefix parse function for ; found"},
+		{"a ? : 2", "no prefix parse function for : found"},
+		{"a ? 1 :", "no prefix parse function for EOF found"},
+	}
+
+	for _, test := range tests {
+		parser := New(lexer.New(test.input))
+		program := parser.ParseProgram()
+
+		errors := parser.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected parser errors for %q, got none", test.input)
+			continue
+		}
+		if errors[0] != test.expectedError {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", test.input, test.expectedError, errors[0])
+		}
+
+		_ = program.String()
+	}
+}
+
+func TestWhileStatement(t *testing.T) {
+	input := `while (x < 10) { x += 1; }`
+
+	program := create(t, input)
+
+	checkStatementLength(t, program.Statements, 1)
+
+	statement, ok := program.Statements[0].(*ast.WhileStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.WhileStatement. got=%T", program.Statements[0])
+	}
+	if !testInfixExpression(t, statement.Condition, "x", "<", 10) {
+		return
+	}
+
+	checkStatementLength(t, statement.Body.Statements, 1)
+
+	body, ok := statement.Body.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("Body.Statements[0] is not ast.ExpressionStatement. got=%T", statement.Body.Statements[0])
+	}
+	if body.String() != "(x += 1)" {
+		t.Errorf("body.String() wrong. got=%q", body.String())
+	}
+}
+
+func TestForStatement(t *testing.T) {
+	tests := []struct {
+		input             string
+		expectedInit      string
+		expectedCondition string
+		expectedUpdate    string
+	}{
+		{"for (let i = 0; i < 10; i += 1) { x }", "let i = 0;", "(i < 10)", "(i += 1)"},
+		{"for (i = 0; i < 10; i += 1) { x }", "(i = 0)", "(i < 10)", "(i += 1)"},
+		{"for (; i < 10;) { x }", "", "(i < 10)", ""},
+		{"for (;;) { x }", "", "", ""},
+	}
+
+	for _, test := range tests {
+		program := create(t, test.input)
+
+		checkStatementLength(t, program.Statements, 1)
+
+		statement, ok := program.Statements[0].(*ast.ForStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ForStatement. got=%T", program.Statements[0])
+		}
+
+		if test.expectedInit == "" {
+			if statement.Init != nil {
+				t.Errorf("statement.Init not nil for %q. got=%s", test.input, statement.Init)
+			}
+		} else if statement.Init == nil || statement.Init.String() != test.expectedInit {
+			t.Errorf("statement.Init wrong for %q. expected=%q, got=%v", test.input, test.expectedInit, statement.Init)
+		}
+
+		if test.expectedCondition == "" {
+			if statement.Condition != nil {
+				t.Errorf("statement.Condition not nil for %q. got=%s", test.input, statement.Condition)
+			}
+		} else if statement.Condition == nil || statement.Condition.String() != test.expectedCondition {
+			t.Errorf("statement.Condition wrong for %q. expected=%q, got=%v", test.input, test.expectedCondition, statement.Condition)
+		}
+
+		if test.expectedUpdate == "" {
+			if statement.Update != nil {
+				t.Errorf("statement.Update not nil for %q. got=%s", test.input, statement.Update)
+			}
+		} else if statement.Update == nil || statement.Update.String() != test.expectedUpdate {
+			t.Errorf("statement.Update wrong for %q. expected=%q, got=%v", test.input, test.expectedUpdate, statement.Update)
+		}
+
+		checkStatementLength(t, statement.Body.Statements, 1)
+	}
+}
+
+func TestForInStatement(t *testing.T) {
+	input := `for (x in xs) { total += x; }`
+
+	program := create(t, input)
+
+	checkStatementLength(t, program.Statements, 1)
+
+	statement, ok := program.Statements[0].(*ast.ForInStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ForInStatement. got=%T", program.Statements[0])
+	}
+	if !testIdentifier(t, statement.Variable, "x") {
+		return
+	}
+	if !testIdentifier(t, statement.Iterable, "xs") {
+		return
+	}
+
+	checkStatementLength(t, statement.Body.Statements, 1)
+}
+
+func TestNestedLoopsWithBreakAndContinue(t *testing.T) {
+	input := `
+while (true) {
+	for (x in xs) {
+		if (x == 0) { continue; }
+		let f = fn() { x };
+		while (x > 0) { x -= 1; break; }
+	}
+	break;
+}`
+
+	program := create(t, input)
+
+	checkStatementLength(t, program.Statements, 1)
+
+	outer, ok := program.Statements[0].(*ast.WhileStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.WhileStatement. got=%T", program.Statements[0])
+	}
+
+	checkStatementLength(t, outer.Body.Statements, 2)
+
+	inner, ok := outer.Body.Statements[0].(*ast.ForInStatement)
+	if !ok {
+		t.Fatalf("outer.Body.Statements[0] is not ast.ForInStatement. got=%T", outer.Body.Statements[0])
+	}
+
+	checkStatementLength(t, inner.Body.Statements, 3)
+
+	if _, ok := inner.Body.Statements[2].(*ast.WhileStatement); !ok {
+		t.Fatalf("inner.Body.Statements[2] is not ast.WhileStatement. got=%T", inner.Body.Statements[2])
+	}
+	if _, ok := outer.Body.Statements[1].(*ast.BreakStatement); !ok {
+		t.Fatalf("outer.Body.Statements[1] is not ast.BreakStatement. got=%T", outer.Body.Statements[1])
+	}
+}
+
+func TestLoopControlOutsideLoop(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"break;", "break statement outside of a loop"},
+		{"continue;", "continue statement outside of a loop"},
+		{"if (x) { break; }", "break statement outside of a loop"},
+		{"while (x) { fn() { continue; } }", "continue statement outside of a loop"},
+	}
+
+	for _, test := range tests {
+		parser := New(lexer.New(test.input))
+		parser.ParseProgram()
+
+		errors := parser.Errors()
+		if len(errors) != 1 {
+			t.Errorf("expected 1 parser error for %q, got=%v", test.input, errors)
+			continue
+		}
+		if errors[0] != test.expectedError {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", test.input, test.expectedError, errors[0])
+		}
+	}
+}
+
+func TestMatchExpression(t *testing.T) {
+	input := `match (value) {
+	1 => "one",
+	-1 => "minus one",
+	[a, b] => a + b,
+	{"k": v} => v,
+	n if n > 10 => "big",
+	_ => "other",
+}`
+
+	program := create(t, input)
+
+	checkStatementLength(t, program.Statements, 1)
+
+	statement, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	match, ok := statement.Expression.(*ast.MatchExpression)
+	if !ok {
+		t.Fatalf("expression is not ast.MatchExpression. got=%T", statement.Expression)
+	}
+	if !testIdentifier(t, match.Subject, "value") {
+		return
+	}
+
+	tests := []struct {
+		expectedPattern string
+		expectedGuard   string
+		expectedBody    string
+	}{
+		{"1", "", `"one"`},
+		{"(-1)", "", `"minus one"`},
+		{"[a, b]", "", "(a + b)"},
+		{`{"k": v}`, "", "v"},
+		{"n", "(n > 10)", `"big"`},
+		{"_", "", `"other"`},
+	}
+
+	if len(match.Arms) != len(tests) {
+		t.Fatalf("match.Arms has wrong length. want=%d, got=%d", len(tests), len(match.Arms))
+	}
+
+	for i, test := range tests {
+		arm := match.Arms[i]
+
+		if arm.Pattern.String() != test.expectedPattern {
+			t.Errorf("arms[%d] pattern wrong. expected=%q, got=%q", i, test.expectedPattern, arm.Pattern.String())
+		}
+
+		guard := ""
+		if arm.Guard != nil {
+			guard = arm.Guard.String()
+		}
+		if guard != test.expectedGuard {
+			t.Errorf("arms[%d] guard wrong. expected=%q, got=%q", i, test.expectedGuard, guard)
+		}
+
+		if arm.Body.String() != test.expectedBody {
+			t.Errorf("arms[%d] body wrong. expected=%q, got=%q", i, test.expectedBody, arm.Body.String())
+		}
+	}
+}
+
+func TestMatchPatternTypes(t *testing.T) {
+	input := `match (x) { 1 => 0, a => 0, _ => 0, [_, [b]] => 0, {"k": {"j": c}} => 0 }`
+
+	program := create(t, input)
+
+	match := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.MatchExpression)
+
+	if _, ok := match.Arms[0].Pattern.(*ast.LiteralPattern); !ok {
+		t.Errorf("arms[0] pattern is not ast.LiteralPattern. got=%T", match.Arms[0].Pattern)
+	}
+	if _, ok := match.Arms[1].Pattern.(*ast.Identifier); !ok {
+		t.Errorf("arms[1] pattern is not ast.Identifier. got=%T", match.Arms[1].Pattern)
+	}
+	if _, ok := match.Arms[2].Pattern.(*ast.WildcardPattern); !ok {
+		t.Errorf("arms[2] pattern is not ast.WildcardPattern. got=%T", match.Arms[2].Pattern)
+	}
+
+	array, ok := match.Arms[3].Pattern.(*ast.ArrayPattern)
+	if !ok {
+		t.Fatalf("arms[3] pattern is not ast.ArrayPattern. got=%T", match.Arms[3].Pattern)
+	}
+	if _, ok := array.Elements[1].(*ast.ArrayPattern); !ok {
+		t.Errorf("nested element is not ast.ArrayPattern. got=%T", array.Elements[1])
+	}
+
+	hash, ok := match.Arms[4].Pattern.(*ast.HashPattern)
+	if !ok {
+		t.Fatalf("arms[4] pattern is not ast.HashPattern. got=%T", match.Arms[4].Pattern)
+	}
+	if _, ok := hash.Pairs[0].Value.(*ast.HashPattern); !ok {
+		t.Errorf("nested value is not ast.HashPattern. got=%T", hash.Pairs[0].Value)
+	}
+}
+
+func TestMatchExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"match (x) { 1 \"one\" }", "expected next token to be =>, got STRING instead"},
+		{"match (x) { 1 => 1 2 => 2 }", "expected next token to be ,, got INT instead"},
+		{"match (x) { + => 1 }", "expected pattern, got + instead"},
+		{"match (x) { {[a]: 1} => 1 }", "expected pattern, got [ instead"},
+	}
+
+	for _, test := range tests {
+		parser := New(lexer.New(test.input))
+		parser.ParseProgram()
+
+		errors := parser.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected parser errors for %q, got none", test.input)
+			continue
+		}
+		if errors[0] != test.expectedError {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", test.input, test.expectedError, errors[0])
+		}
+	}
+}
+
+func TestDestructuringLetStatements(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedPattern string
+	}{
+		{"let [a, b] = arr;", "[a, b]"},
+		{"let [a, b, ...rest] = arr;", "[a, b, ...rest]"},
+		{"let [first, [x, y]] = pairs;", "[first, [x, y]]"},
+		{"let [a = 1, b = x + 1] = arr;", "[a = 1, b = (x + 1)]"},
+		{"let {name, age: years} = person;", "{name, age: years}"},
+		{"let {name = \"anon\", age: years = 0} = person;", `{name = "anon", age: years = 0}`},
+		{"let {address: {city}, \"zip code\": zip} = person;", `{address: {city}, "zip code": zip}`},
+		{"let [{id}, ...others] = people;", "[{id}, ...others]"},
+	}
+
+	for _, test := range tests {
+		program := create(t, test.input)
+
+		checkStatementLength(t, program.Statements, 1)
+
+		statement, ok := program.Statements[0].(*ast.LetStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.LetStatement. got=%T", program.Statements[0])
+		}
+		if statement.Name != nil {
+			t.Errorf("statement.Name not nil for %q. got=%s", test.input, statement.Name)
+		}
+		if statement.Pattern == nil {
+			t.Fatalf("statement.Pattern is nil for %q", test.input)
+		}
+		if statement.Pattern.String() != test.expectedPattern {
+			t.Errorf("pattern wrong. expected=%q, got=%q", test.expectedPattern, statement.Pattern.String())
+		}
+	}
+}
+
+func TestDestructuringPatternNodes(t *testing.T) {
+	input := `let [a = 1, ...rest] = arr; let {name, age: years} = person;`
+
+	program := create(t, input)
+
+	checkStatementLength(t, program.Statements, 2)
+
+	array, ok := program.Statements[0].(*ast.LetStatement).Pattern.(*ast.ArrayPattern)
+	if !ok {
+		t.Fatalf("pattern is not ast.ArrayPattern. got=%T", program.Statements[0].(*ast.LetStatement).Pattern)
+	}
+
+	withDefault, ok := array.Elements[0].(*ast.DefaultPattern)
+	if !ok {
+		t.Fatalf("array.Elements[0] is not ast.DefaultPattern. got=%T", array.Elements[0])
+	}
+	if !testParameter(t, withDefault.Target, "a") {
+		return
+	}
+	if !testLiteralExpression(t, withDefault.Default, 1) {
+		return
+	}
+
+	rest, ok := array.Elements[1].(*ast.RestPattern)
+	if !ok {
+		t.Fatalf("array.Elements[1] is not ast.RestPattern. got=%T", array.Elements[1])
+	}
+	if !testIdentifier(t, rest.Name, "rest") {
+		return
+	}
+
+	hash, ok := program.Statements[1].(*ast.LetStatement).Pattern.(*ast.HashPattern)
+	if !ok {
+		t.Fatalf("pattern is not ast.HashPattern. got=%T", program.Statements[1].(*ast.LetStatement).Pattern)
+	}
+	if !testIdentifier(t, hash.Pairs[0].Key, "name") || !testParameter(t, hash.Pairs[0].Value, "name") {
+		return
+	}
+	if !testIdentifier(t, hash.Pairs[1].Key, "age") || !testParameter(t, hash.Pairs[1].Value, "years") {
+		return
+	}
+}
+
+func TestDestructuringFunctionParameters(t *testing.T) {
+	input := `fn([a, b], {name}, c) { a + b }`
+
+	program := create(t, input)
+
+	function := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+
+	if len(function.Parameters) != 3 {
+		t.Fatalf("function literal parameters wrong. want 3, got=%d", len(function.Parameters))
+	}
+	if _, ok := function.Parameters[0].(*ast.ArrayPattern); !ok {
+		t.Errorf("Parameters[0] is not ast.ArrayPattern. got=%T", function.Parameters[0])
+	}
+	if _, ok := function.Parameters[1].(*ast.HashPattern); !ok {
+		t.Errorf("Parameters[1] is not ast.HashPattern. got=%T", function.Parameters[1])
+	}
+	testParameter(t, function.Parameters[2], "c")
+
+	if function.String() != "fn([a, b], {name}, c) (a + b)" {
+		t.Errorf("function.String() wrong. got=%q", function.String())
+	}
+}
+
+func TestUnderscoreAsName(t *testing.T) {
+	program := create(t, `let _ = 5; fn(_, b) { b }; let [a, _] = pair;`)
+
+	checkStatementLength(t, program.Statements, 3)
+
+	let, ok := program.Statements[0].(*ast.LetStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.LetStatement. got=%T", program.Statements[0])
+	}
+	if !testIdentifier(t, let.Name, "_") {
+		return
+	}
+	if !testLiteralExpression(t, let.Value, 5) {
+		return
+	}
+
+	function := program.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	if len(function.Parameters) != 2 {
+		t.Fatalf("function literal parameters wrong. want 2, got=%d", len(function.Parameters))
+	}
+	testParameter(t, function.Parameters[0], "_")
+	testParameter(t, function.Parameters[1], "b")
+
+	array, ok := program.Statements[2].(*ast.LetStatement).Pattern.(*ast.ArrayPattern)
+	if !ok {
+		t.Fatalf("pattern is not ast.ArrayPattern. got=%T", program.Statements[2].(*ast.LetStatement).Pattern)
+	}
+	if _, ok := array.Elements[1].(*ast.WildcardPattern); !ok {
+		t.Errorf("array.Elements[1] is not ast.WildcardPattern. got=%T", array.Elements[1])
+	}
+}
+
+func TestDestructuringErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"let [...rest, a] = arr;", "rest element must be last in array pattern"},
+		{"let [...] = arr;", "expected next token to be IDENT, got ] instead"},
+		{"let {a b} = h;", "expected next token to be ,, got IDENT instead"},
+		{"let 5 = x;", "expected next token to be IDENT, got INT instead"},
+		{"fn(1) {}", "expected parameter name or pattern, got INT instead"},
+		{"fn(a, +) {}", "expected parameter name or pattern, got + instead"},
+	}
+
+	for _, test := range tests {
+		parser := New(lexer.New(test.input))
+		parser.ParseProgram()
+
+		errors := parser.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected parser errors for %q, got none", test.input)
+			continue
+		}
+		if errors[0] != test.expectedError {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", test.input, test.expectedError, errors[0])
+		}
+	}
+}
+
+func TestCallExpressionParsing(t *testing.T) {
+	input := "add(1, 2 * 3, 4 + 5);"
+
+	program := create(t, input)
+
+	checkStatementLength(t, program.Statements, 1)
+
+	statement, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	call, ok := statement.Expression.(*ast.CallExpression)
+	if !ok {
+		t.Fatalf("expression is not ast.CallExpression. got=%T", statement.Expression)
+	}
+	if !testIdentifier(t, call.Function, "add") {
+		return
+	}
+	if len(call.Arguments) != 3 {
+		t.Fatalf("wrong length of arguments. got=%d", len(call.Arguments))
+	}
+
+	testLiteralExpression(t, call.Arguments[0], 1)
+	testInfixExpression(t, call.Arguments[1], 2, "*", 3)
+	testInfixExpression(t, call.Arguments[2], 4, "+", 5)
+}
+
+func TestIndexExpressionParsing(t *testing.T) {
+	input := "myArray[1 + 1]"
+
+	program := create(t, input)
+
+	checkStatementLength(t, program.Statements, 1)
+
+	statement, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	index, ok := statement.Expression.(*ast.IndexExpression)
+	if !ok {
+		t.Fatalf("expression is not ast.IndexExpression. got=%T", statement.Expression)
+	}
+	if !testIdentifier(t, index.Left, "myArray") {
+		return
+	}
+	testInfixExpression(t, index.Index, 1, "+", 1)
+}
+
+func TestDefaultAndVariadicParameters(t *testing.T) {
+	input := `fn(a, b = 10, ...rest) { a }`
+
+	program := create(t, input)
+
+	function := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+
+	if len(function.Parameters) != 3 {
+		t.Fatalf("function literal parameters wrong. want 3, got=%d", len(function.Parameters))
+	}
+
+	testParameter(t, function.Parameters[0], "a")
+
+	withDefault, ok := function.Parameters[1].(*ast.DefaultPattern)
+	if !ok {
+		t.Fatalf("Parameters[1] is not ast.DefaultPattern. got=%T", function.Parameters[1])
+	}
+	testParameter(t, withDefault.Target, "b")
+	testLiteralExpression(t, withDefault.Default, 10)
+
+	rest, ok := function.Parameters[2].(*ast.RestPattern)
+	if !ok {
+		t.Fatalf("Parameters[2] is not ast.RestPattern. got=%T", function.Parameters[2])
+	}
+	testIdentifier(t, rest.Name, "rest")
+
+	if function.String() != "fn(a, b = 10, ...rest) a" {
+		t.Errorf("function.String() wrong. got=%q", function.String())
+	}
+}
+
+func TestSpreadAndNamedArguments(t *testing.T) {
+	input := `f(1, ...xs, b: 2, c: x + 1)`
+
+	program := create(t, input)
+
+	call := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.CallExpression)
+
+	if len(call.Arguments) != 4 {
+		t.Fatalf("wrong length of arguments. got=%d", len(call.Arguments))
+	}
+
+	testLiteralExpression(t, call.Arguments[0], 1)
+
+	spread, ok := call.Arguments[1].(*ast.SpreadExpression)
+	if !ok {
+		t.Fatalf("Arguments[1] is not ast.SpreadExpression. got=%T", call.Arguments[1])
+	}
+	testIdentifier(t, spread.Value, "xs")
+
+	named, ok := call.Arguments[2].(*ast.NamedArgument)
+	if !ok {
+		t.Fatalf("Arguments[2] is not ast.NamedArgument. got=%T", call.Arguments[2])
+	}
+	testIdentifier(t, named.Name, "b")
+	testLiteralExpression(t, named.Value, 2)
+
+	named, ok = call.Arguments[3].(*ast.NamedArgument)
+	if !ok {
+		t.Fatalf("Arguments[3] is not ast.NamedArgument. got=%T", call.Arguments[3])
+	}
+	testInfixExpression(t, named.Value, "x", "+", 1)
+
+	if call.String() != "f(1, ...xs, b: 2, c: (x + 1))" {
+		t.Errorf("call.String() wrong. got=%q", call.String())
+	}
+}
+
+func TestFunctionLiteralName(t *testing.T) {
+	input := `let add = fn(a, b) { a + b }; fn() { 1 };`
+
+	program := create(t, input)
+
+	named := program.Statements[0].(*ast.LetStatement).Value.(*ast.FunctionLiteral)
+	if named.Name != "add" {
+		t.Errorf("function.Name not %q. got=%q", "add", named.Name)
+	}
+
+	anonymous := program.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	if anonymous.Name != "" {
+		t.Errorf("function.Name not empty. got=%q", anonymous.Name)
+	}
+}
+
+func TestParameterAndArgumentErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"fn(...rest, a) {}", "rest parameter must be last"},
+		{"fn(a = ) {}", "no prefix parse function for ) found"},
+		{"f(a: 1, 2)", "positional argument after named argument"},
+		{"f(a: 1, ...xs)", "positional argument after named argument"},
+		{"f(a: 1, a: 2)", "duplicate named argument a"},
+		{"f(1, 2", "expected next token to be ), got EOF instead"},
+	}
+
+	for _, test := range tests {
+		parser := New(lexer.New(test.input))
+		parser.ParseProgram()
+
+		errors := parser.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected parser errors for %q, got none", test.input)
+			continue
+		}
+		if errors[0] != test.expectedError {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", test.input, test.expectedError, errors[0])
+		}
+	}
+}
+
+func TestThrowStatement(t *testing.T) {
+	input := `throw error("boom", 42);`
+
+	program := create(t, input)
+
+	checkStatementLength(t, program.Statements, 1)
+
+	statement, ok := program.Statements[0].(*ast.ThrowStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ThrowStatement. got=%T", program.Statements[0])
+	}
+
+	call, ok := statement.Value.(*ast.CallExpression)
+	if !ok {
+		t.Fatalf("statement.Value is not ast.CallExpression. got=%T", statement.Value)
+	}
+	if !testIdentifier(t, call.Function, "error") {
+		return
+	}
+	if statement.String() != `throw error("boom", 42);` {
+		t.Errorf("statement.String() wrong. got=%q", statement.String())
+	}
+}
+
+func TestTryExpression(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectCatch     bool
+		expectedParam   string
+		expectedFinally bool
+	}{
+		{"try { risky() } catch (e) { e }", true, "e", false},
+		{"try { risky() } finally { cleanup() }", false, "", true},
+		{"try { risky() } catch (err) { err } finally { cleanup() }", true, "err", true},
+	}
+
+	for _, test := range tests {
+		program := create(t, test.input)
+
+		checkStatementLength(t, program.Statements, 1)
+
+		statement, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T", program.Statements[0])
+		}
+
+		try, ok := statement.Expression.(*ast.TryExpression)
+		if !ok {
+			t.Fatalf("expression is not ast.TryExpression. got=%T", statement.Expression)
+		}
+
+		checkStatementLength(t, try.Block.Statements, 1)
+
+		if test.expectCatch {
+			if try.Catch == nil {
+				t.Fatalf("try.Catch is nil for %q", test.input)
+			}
+			if !testIdentifier(t, try.CatchParameter, test.expectedParam) {
+				return
+			}
+		} else if try.Catch != nil {
+			t.Errorf("try.Catch not nil for %q", test.input)
+		}
+
+		if test.expectedFinally != (try.Finally != nil) {
+			t.Errorf("try.Finally wrong for %q. got=%v", test.input, try.Finally)
+		}
+	}
+}
+
+func TestTryExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"try { x }", "try expression requires a catch or finally block"},
+		{"try { x } catch { y }", "expected next token to be (, got { instead"},
+		{"try { x } catch (1) { y }", "expected next token to be IDENT, got INT instead"},
+		{"try x", "expected next token to be {, got IDENT instead"},
+	}
+
+	for _, test := range tests {
+		parser := New(lexer.New(test.input))
+		parser.ParseProgram()
+
+		errors := parser.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected parser errors for %q, got none", test.input)
+			continue
+		}
+		if errors[0] != test.expectedError {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", test.input, test.expectedError, errors[0])
+		}
+	}
+}
+
+func TestNodePositions(t *testing.T) {
+	input := `let f = fn(x) {
+  g(x)
+};`
+
+	program := create(t, input)
+
+	let := program.Statements[0].(*ast.LetStatement)
+	if let.Token.Line != 1 || let.Token.Column != 1 {
+		t.Errorf("let position wrong. got=%d:%d", let.Token.Line, let.Token.Column)
+	}
+
+	function := let.Value.(*ast.FunctionLiteral)
+	if function.Token.Line != 1 || function.Token.Column != 9 {
+		t.Errorf("function position wrong. got=%d:%d", function.Token.Line, function.Token.Column)
+	}
+
+	call := function.Body.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.CallExpression)
+	callee := call.Function.(*ast.Identifier)
+	if callee.Token.Line != 2 || callee.Token.Column != 3 {
+		t.Errorf("callee position wrong. got=%d:%d", callee.Token.Line, callee.Token.Column)
+	}
+	if call.Token.Line != 2 || call.Token.Column != 4 {
+		t.Errorf("call position wrong. got=%d:%d", call.Token.Line, call.Token.Column)
+	}
+}
+
+func TestImportAndExportStatements(t *testing.T) {
+	input := `import "lib/math.mk" as m;
+export let answer = m.add(40, 2);
+export let [a, b] = pair;`
+
+	program := create(t, input)
+
+	checkStatementLength(t, program.Statements, 3)
+
+	imp, ok := program.Statements[0].(*ast.ImportStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ImportStatement. got=%T", program.Statements[0])
+	}
+	if imp.Path.Value != "lib/math.mk" {
+		t.Errorf("imp.Path.Value not %q. got=%q", "lib/math.mk", imp.Path.Value)
+	}
+	if !testIdentifier(t, imp.Alias, "m") {
+		return
+	}
+
+	export, ok := program.Statements[1].(*ast.ExportStatement)
+	if !ok {
+		t.Fatalf("program.Statements[1] is not ast.ExportStatement. got=%T", program.Statements[1])
+	}
+	if !testLetStatement(t, export.Statement, "answer") {
+		return
+	}
+
+	call, ok := export.Statement.Value.(*ast.CallExpression)
+	if !ok {
+		t.Fatalf("export value is not ast.CallExpression. got=%T", export.Statement.Value)
+	}
+	member, ok := call.Function.(*ast.MemberExpression)
+	if !ok {
+		t.Fatalf("call.Function is not ast.MemberExpression. got=%T", call.Function)
+	}
+	if !testIdentifier(t, member.Object, "m") || !testIdentifier(t, member.Property, "add") {
+		return
+	}
+
+	if program.String() != `import "lib/math.mk" as m;export let answer = m.add(40, 2);export let [a, b] = pair;` {
+		t.Errorf("program.String() wrong. got=%q", program.String())
+	}
+}
+
+func TestImportAndExportErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"import lib as m;", "expected next token to be STRING, got IDENT instead"},
+		{`import "lib.mk";`, "expected next token to be AS, got ; instead"},
+		{"export x;", "expected next token to be LET, got IDENT instead"},
+		{`if (x) { import "lib.mk" as m; }`, "import statements are only allowed at the top level"},
+		{`fn() { export let x = 1; }`, "export statements are only allowed at the top level"},
+		{"m.1", "expected next token to be IDENT, got INT instead"},
+	}
+
+	for _, test := range tests {
+		parser := New(lexer.New(test.input))
+		parser.ParseProgram()
+
+		errors := parser.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected parser errors for %q, got none", test.input)
+			continue
+		}
+		if errors[0] != test.expectedError {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", test.input, test.expectedError, errors[0])
+		}
+	}
+}
+
+func TestTypeAnnotations(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x: int = 5;", "let x: int = 5;"},
+		{"let x = 5;", "let x = 5;"},
+		{"fn(a: int, b: string) -> bool { true }", "fn(a: int, b: string) -> bool true"},
+		{"fn(a: int = 1, b, ...rest) { a }", "fn(a: int = 1, b, ...rest) a"},
+		{"let f: fn(int, int) -> int = fn(a, b) { a + b };", "let f: fn(int, int) -> int = fn(a, b) (a + b);"},
+		{"fn() -> fn() -> int { fn() -> int { 1 } }", "fn() -> fn() -> int fn() -> int 1"},
+		{"let x = 1 - -2;", "let x = (1 - (-2));"},
+	}
+
+	for _, tt := range tests {
+		program := create(t, tt.input)
+
+		if program.String() != tt.expected {
+			t.Errorf("input %q: wrong String. expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+
+	program := create(t, "let f = fn(a: int) -> bool { true };")
+	let := program.Statements[0].(*ast.LetStatement)
+	function := let.Value.(*ast.FunctionLiteral)
+
+	parameter, ok := function.Parameters[0].(*ast.Identifier)
+	if !ok {
+		t.Fatalf("Parameters[0] is not ast.Identifier. got=%T", function.Parameters[0])
+	}
+
+	named, ok := parameter.Type.(*ast.NamedType)
+	if !ok || named.Name != "int" {
+		t.Errorf("parameter.Type wrong. got=%#v", parameter.Type)
+	}
+
+	named, ok = function.ReturnType.(*ast.NamedType)
+	if !ok || named.Name != "bool" {
+		t.Errorf("function.ReturnType wrong. got=%#v", function.ReturnType)
+	}
+}
+
+func TestTypeAnnotationErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"let x: = 5;", "expected type, got = instead"},
+		{"let x: int 5;", "expected next token to be =, got INT instead"},
+		{"fn(a: 1) { a }", "expected type, got INT instead"},
+		{"fn() -> { 1 }", "expected type, got { instead"},
+		{"let f: fn(int) = 1;", "expected next token to be ->, got = instead"},
+	}
+
+	for _, test := range tests {
+		parser := New(lexer.New(test.input))
+		parser.ParseProgram()
+
+		errors := parser.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected parser errors for %q, got none", test.input)
+			continue
+		}
+		if errors[0] != test.expectedError {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", test.input, test.expectedError, errors[0])
+		}
+	}
+}
+
+func TestPositionedErrors(t *testing.T) {
+	input := "let x = 1;\nlet = 2;\nbreak;"
+
+	parser := New(lexer.New(input))
+	parser.ParseProgram()
+
+	expected := []string{
+		"2:5: expected next token to be IDENT, got = instead",
+		"2:5: no prefix parse function for = found",
+		"3:1: break statement outside of a loop",
+	}
+
+	errors := parser.PositionedErrors()
+	if len(errors) != len(expected) {
+		t.Fatalf("wrong number of errors. expected=%d, got=%v", len(expected), errors)
+	}
+
+	for i, err := range errors {
+		if err.Error() != expected[i] {
+			t.Errorf("errors[%d] wrong. expected=%q, got=%q", i, expected[i], err.Error())
+		}
+		if parser.Errors()[i] != err.Message {
+			t.Errorf("Errors()[%d] does not match message. got=%q", i, parser.Errors()[i])
+		}
+	}
+}
+
+func TestTrace(t *testing.T) {
+	var out strings.Builder
+
+	parser := New(lexer.New("-a * b"))
+	parser.SetTrace(&out)
+	parser.ParseProgram()
+	checkParserErrors(t, parser)
+
+	expected := `BEGIN parseExpression (current: "-", peek: "a")
+  BEGIN parsePrefixExpression (current: "-", peek: "a")
+    BEGIN parseExpression (current: "a", peek: "*")
+    END parseExpression (current: "a", peek: "*")
+  END parsePrefixExpression (current: "a", peek: "*")
+  BEGIN parseInfixExpression (current: "*", peek: "b")
+    BEGIN parseExpression (current: "b", peek: EOF)
+    END parseExpression (current: "b", peek: EOF)
+  END parseInfixExpression (current: "b", peek: EOF)
+END parseExpression (current: "b", peek: EOF)
+`
+
+	if out.String() != expected {
+		t.Errorf("trace wrong.\nexpected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
@@ -0,0 +1,191 @@
+package parser
+
+import (
+	"fmt"
+	"gomonkey/ast"
+	"gomonkey/token"
+)
+
+func (parser *Parser) parsePattern() ast.Pattern {
+	switch parser.currentToken.Type {
+	case token.IDENT:
+		if parser.currentToken.Literal == "_" {
+			return &ast.WildcardPattern{Token: parser.currentToken}
+		}
+		return &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal}
+	case token.INT, token.STRING, token.TRUE, token.FALSE, token.MINUS:
+		return parser.parseLiteralPattern()
+	case token.OPEN_BRACKET:
+		return parser.parseArrayPattern()
+	case token.OPEN_CURLY:
+		return parser.parseHashPattern()
+	default:
+		parser.patternError(parser.currentToken)
+		return nil
+	}
+}
+
+func (parser *Parser) parseLiteralPattern() ast.Pattern {
+	pattern := &ast.LiteralPattern{Token: parser.currentToken}
+
+	if parser.currentTokenIs(token.MINUS) {
+		if !parser.expectPeek(token.INT) {
+			return nil
+		}
+
+		right := parser.parseIntegerLiteral()
+		if right == nil {
+			return nil
+		}
+
+		pattern.Value = &ast.PrefixExpression{Token: pattern.Token, Operator: pattern.Token.Literal, Right: right}
+		return pattern
+	}
+
+	pattern.Value = parser.prefixParseFns[parser.currentToken.Type]()
+	if pattern.Value == nil {
+		return nil
+	}
+
+	return pattern
+}
+
+func (parser *Parser) parseArrayPattern() ast.Pattern {
+	pattern := &ast.ArrayPattern{Token: parser.currentToken}
+	pattern.Elements = []ast.Pattern{}
+
+	for !parser.peekTokenIs(token.CLOSE_BRACKET) {
+		parser.nextToken()
+
+		if parser.currentTokenIs(token.ELLIPSIS) {
+			rest := parser.parseRestPattern()
+			if rest == nil {
+				return nil
+			}
+			pattern.Elements = append(pattern.Elements, rest)
+
+			if !parser.peekTokenIs(token.CLOSE_BRACKET) {
+				parser.addError(parser.currentToken, "rest element must be last in array pattern")
+				return nil
+			}
+			break
+		}
+
+		element := parser.parsePatternWithDefault()
+		if element == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, element)
+
+		if !parser.peekTokenIs(token.CLOSE_BRACKET) && !parser.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	parser.nextToken()
+	pattern.End = parser.currentToken
+
+	return pattern
+}
+
+func (parser *Parser) parseHashPattern() ast.Pattern {
+	pattern := &ast.HashPattern{Token: parser.currentToken}
+	pattern.Pairs = []*ast.HashPatternPair{}
+
+	for !parser.peekTokenIs(token.CLOSE_CURLY) {
+		parser.nextToken()
+
+		pair := parser.parseHashPatternPair()
+		if pair == nil {
+			return nil
+		}
+		pattern.Pairs = append(pattern.Pairs, pair)
+
+		if !parser.peekTokenIs(token.CLOSE_CURLY) && !parser.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	parser.nextToken()
+	pattern.End = parser.currentToken
+
+	return pattern
+}
+
+func (parser *Parser) parseHashPatternPair() *ast.HashPatternPair {
+	pair := &ast.HashPatternPair{}
+
+	switch parser.currentToken.Type {
+	case token.IDENT:
+		key := &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal}
+		pair.Key = key
+
+		if !parser.peekTokenIs(token.COLON) {
+			pair.Value = parser.parseDefault(key)
+			if pair.Value == nil {
+				return nil
+			}
+			return pair
+		}
+	case token.STRING, token.INT, token.TRUE, token.FALSE:
+		pair.Key = parser.prefixParseFns[parser.currentToken.Type]()
+	default:
+		parser.patternError(parser.currentToken)
+		return nil
+	}
+
+	if pair.Key == nil || !parser.expectPeek(token.COLON) {
+		return nil
+	}
+
+	parser.nextToken()
+
+	pair.Value = parser.parsePatternWithDefault()
+	if pair.Value == nil {
+		return nil
+	}
+
+	return pair
+}
+
+func (parser *Parser) parsePatternWithDefault() ast.Pattern {
+	pattern := parser.parsePattern()
+	if pattern == nil {
+		return nil
+	}
+
+	return parser.parseDefault(pattern)
+}
+
+func (parser *Parser) parseDefault(target ast.Pattern) ast.Pattern {
+	if !parser.peekTokenIs(token.ASSIGN) {
+		return target
+	}
+
+	parser.nextToken()
+	pattern := &ast.DefaultPattern{Token: parser.currentToken, Target: target}
+
+	parser.nextToken()
+	pattern.Default = parser.parseExpression(ASSIGN)
+	if pattern.Default == nil {
+		return nil
+	}
+
+	return pattern
+}
+
+func (parser *Parser) parseRestPattern() ast.Pattern {
+	pattern := &ast.RestPattern{Token: parser.currentToken}
+
+	if !parser.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	pattern.Name = &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal}
+	return pattern
+}
+
+func (parser *Parser) patternError(tok token.Token) {
+	msg := fmt.Sprintf("expected pattern, got %s instead", tok.Type)
+	parser.addError(parser.currentToken, msg)
+}
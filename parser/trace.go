@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"fmt"
+	"gomonkey/token"
+	"io"
+	"strings"
+)
+
+func (parser *Parser) SetTrace(w io.Writer) {
+	parser.tracer = w
+	parser.traceLevel = 0
+}
+
+func (parser *Parser) trace(name string) string {
+	if parser.tracer != nil {
+		parser.tracePrint("BEGIN " + name)
+		parser.traceLevel++
+	}
+	return name
+}
+
+func (parser *Parser) untrace(name string) {
+	if parser.tracer != nil {
+		parser.traceLevel--
+		parser.tracePrint("END " + name)
+	}
+}
+
+func (parser *Parser) tracePrint(msg string) {
+	fmt.Fprintf(parser.tracer, "%s%s (current: %s, peek: %s)\n",
+		strings.Repeat("  ", parser.traceLevel), msg, traceToken(parser.currentToken), traceToken(parser.peekToken))
+}
+
+func traceToken(tok token.Token) string {
+	if tok.Type == token.EOF {
+		return "EOF"
+	}
+	return fmt.Sprintf("%q", tok.Literal)
+}
@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"fmt"
+	"gomonkey/ast"
+	"gomonkey/token"
+)
+
+func (parser *Parser) parseTypeAnnotation(separator token.TokenType) (ast.TypeExpression, bool) {
+	if !parser.peekTokenIs(separator) {
+		return nil, true
+	}
+
+	parser.nextToken()
+	parser.nextToken()
+
+	typ := parser.parseType()
+	return typ, typ != nil
+}
+
+func (parser *Parser) parseType() ast.TypeExpression {
+	switch parser.currentToken.Type {
+	case token.IDENT:
+		return &ast.NamedType{Token: parser.currentToken, Name: parser.currentToken.Literal}
+	case token.FUNCTION:
+		return parser.parseFunctionType()
+	default:
+		msg := fmt.Sprintf("expected type, got %s instead", parser.currentToken.Type)
+		parser.addError(parser.currentToken, msg)
+		return nil
+	}
+}
+
+func (parser *Parser) parseFunctionType() ast.TypeExpression {
+	typ := &ast.FunctionType{Token: parser.currentToken}
+
+	if !parser.expectPeek(token.OPEN_PARENTHESIS) {
+		return nil
+	}
+
+	typ.Parameters = []ast.TypeExpression{}
+
+	if parser.peekTokenIs(token.CLOSE_PARENTHESIS) {
+		parser.nextToken()
+	} else {
+		for {
+			parser.nextToken()
+
+			parameter := parser.parseType()
+			if parameter == nil {
+				return nil
+			}
+			typ.Parameters = append(typ.Parameters, parameter)
+
+			if !parser.peekTokenIs(token.COMMA) {
+				break
+			}
+			parser.nextToken()
+		}
+
+		if !parser.expectPeek(token.CLOSE_PARENTHESIS) {
+			return nil
+		}
+	}
+
+	if !parser.expectPeek(token.ARROW) {
+		return nil
+	}
+	parser.nextToken()
+
+	typ.Return = parser.parseType()
+	if typ.Return == nil {
+		return nil
+	}
+
+	return typ
+}
@@ -0,0 +1,289 @@
+package resolver
+
+import (
+	"fmt"
+	"gomonkey/ast"
+	"sort"
+)
+
+type Result struct {
+	Definitions map[*ast.Identifier]*Symbol
+	Uses        map[*ast.Identifier]*Symbol
+	Free        map[*ast.FunctionLiteral][]*Symbol
+	Diagnostics []Diagnostic
+}
+
+func (result *Result) Errors() []Diagnostic {
+	errors := []Diagnostic{}
+	for _, diagnostic := range result.Diagnostics {
+		if diagnostic.Severity == Error {
+			errors = append(errors, diagnostic)
+		}
+	}
+	return errors
+}
+
+type resolver struct {
+	scope  *scope
+	result *Result
+}
+
+func Resolve(program *ast.Program, builtins ...string) *Result {
+	global := &function{global: true, freeSymbols: map[*Symbol]*Symbol{}}
+
+	root := newScope(nil, global)
+	for i, name := range builtins {
+		root.symbols[name] = &Symbol{Name: name, Scope: BuiltinScope, Index: i}
+	}
+
+	r := &resolver{
+		scope: newScope(root, global),
+		result: &Result{
+			Definitions: map[*ast.Identifier]*Symbol{},
+			Uses:        map[*ast.Identifier]*Symbol{},
+			Free:        map[*ast.FunctionLiteral][]*Symbol{},
+			Diagnostics: []Diagnostic{},
+		},
+	}
+
+	r.statements(program.Statements)
+	r.closeScope()
+
+	sort.SliceStable(r.result.Diagnostics, func(i, j int) bool {
+		a, b := r.result.Diagnostics[i], r.result.Diagnostics[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	return r.result
+}
+
+func (r *resolver) openScope() {
+	r.scope = newScope(r.scope, r.scope.function)
+}
+
+func (r *resolver) closeScope() {
+	for len(r.scope.pending) > 0 {
+		pending := r.scope.pending[0]
+		r.scope.pending = r.scope.pending[1:]
+		pending()
+	}
+	r.scope = r.scope.outer
+}
+
+func (r *resolver) block(block *ast.BlockStatement) {
+	if block == nil {
+		return
+	}
+
+	r.openScope()
+	r.statements(block.Statements)
+	r.closeScope()
+}
+
+func (r *resolver) statements(statements []ast.Statement) {
+	for _, statement := range statements {
+		r.statement(statement)
+	}
+}
+
+func (r *resolver) statement(statement ast.Statement) {
+	switch statement := statement.(type) {
+	case *ast.LetStatement:
+		r.let(statement)
+	case *ast.ExportStatement:
+		r.let(statement.Statement)
+	case *ast.ImportStatement:
+		r.define(statement.Alias)
+	case *ast.ReturnStatement:
+		r.expression(statement.ReturnValue)
+	case *ast.ThrowStatement:
+		r.expression(statement.Value)
+	case *ast.ExpressionStatement:
+		r.expression(statement.Expression)
+	case *ast.BlockStatement:
+		r.block(statement)
+	case *ast.WhileStatement:
+		r.expression(statement.Condition)
+		r.block(statement.Body)
+	case *ast.ForStatement:
+		r.openScope()
+		if statement.Init != nil {
+			r.statement(statement.Init)
+		}
+		r.expression(statement.Condition)
+		r.expression(statement.Update)
+		r.block(statement.Body)
+		r.closeScope()
+	case *ast.ForInStatement:
+		r.expression(statement.Iterable)
+		r.openScope()
+		r.define(statement.Variable)
+		r.block(statement.Body)
+		r.closeScope()
+	}
+}
+
+func (r *resolver) let(statement *ast.LetStatement) {
+	if statement == nil {
+		return
+	}
+
+	r.expression(statement.Value)
+
+	if statement.Pattern != nil {
+		r.pattern(statement.Pattern, nil)
+	} else if statement.Name != nil {
+		r.define(statement.Name)
+	}
+}
+
+func (r *resolver) expression(expression ast.Expression) {
+	switch expression := expression.(type) {
+	case *ast.Identifier:
+		r.use(expression)
+	case *ast.PrefixExpression:
+		r.expression(expression.Right)
+	case *ast.GroupedExpression:
+		r.expression(expression.Expression)
+	case *ast.InfixExpression:
+		r.expression(expression.Left)
+		r.expression(expression.Right)
+	case *ast.AssignExpression:
+		r.expression(expression.Target)
+		r.expression(expression.Value)
+	case *ast.IfExpression:
+		r.expression(expression.Condition)
+		r.block(expression.Consequence)
+		r.block(expression.Alternative)
+	case *ast.ConditionalExpression:
+		r.expression(expression.Condition)
+		r.expression(expression.Consequence)
+		r.expression(expression.Alternative)
+	case *ast.FunctionLiteral:
+		r.functionLiteral(expression)
+	case *ast.CallExpression:
+		r.expression(expression.Function)
+		for _, argument := range expression.Arguments {
+			r.expression(argument)
+		}
+	case *ast.MemberExpression:
+		r.expression(expression.Object)
+	case *ast.IndexExpression:
+		r.expression(expression.Left)
+		r.expression(expression.Index)
+	case *ast.SpreadExpression:
+		r.expression(expression.Value)
+	case *ast.NamedArgument:
+		r.expression(expression.Value)
+	case *ast.MatchExpression:
+		r.expression(expression.Subject)
+		for _, arm := range expression.Arms {
+			r.openScope()
+			r.pattern(arm.Pattern, nil)
+			r.expression(arm.Guard)
+			r.expression(arm.Body)
+			r.closeScope()
+		}
+	case *ast.TryExpression:
+		r.block(expression.Block)
+		if expression.Catch != nil {
+			r.openScope()
+			r.define(expression.CatchParameter)
+			r.block(expression.Catch)
+			r.closeScope()
+		}
+		r.block(expression.Finally)
+	}
+}
+
+func (r *resolver) functionLiteral(literal *ast.FunctionLiteral) {
+	enclosing := r.scope
+
+	enclosing.pending = append(enclosing.pending, func() {
+		function := &function{outer: enclosing, freeSymbols: map[*Symbol]*Symbol{}, literal: literal}
+
+		saved := r.scope
+		r.scope = newScope(enclosing, function)
+
+		parameters := map[string]bool{}
+		for _, parameter := range literal.Parameters {
+			r.pattern(parameter, parameters)
+		}
+
+		if literal.Body != nil {
+			r.statements(literal.Body.Statements)
+		}
+		r.closeScope()
+
+		r.result.Free[literal] = function.free
+		r.scope = saved
+	})
+}
+
+func (r *resolver) pattern(pattern ast.Pattern, parameters map[string]bool) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if parameters != nil {
+			if parameters[pattern.Value] {
+				r.report(Error, pattern, fmt.Sprintf("duplicate parameter %s in function literal", pattern.Value))
+			}
+			parameters[pattern.Value] = true
+		}
+		r.define(pattern)
+	case *ast.ArrayPattern:
+		for _, element := range pattern.Elements {
+			r.pattern(element, parameters)
+		}
+	case *ast.HashPattern:
+		for _, pair := range pattern.Pairs {
+			r.pattern(pair.Value, parameters)
+		}
+	case *ast.RestPattern:
+		r.pattern(pattern.Name, parameters)
+	case *ast.DefaultPattern:
+		r.expression(pattern.Default)
+		r.pattern(pattern.Target, parameters)
+	}
+}
+
+func (r *resolver) define(ident *ast.Identifier) {
+	if ident == nil {
+		return
+	}
+
+	if shadowed, ok := r.scope.lookupOuter(ident.Value); ok && declaredBefore(shadowed.Definition, ident) {
+		r.report(Warning, ident, fmt.Sprintf("declaration of %s shadows declaration at %d:%d",
+			ident.Value, shadowed.Definition.Token.Line, shadowed.Definition.Token.Column))
+	}
+
+	r.result.Definitions[ident] = r.scope.define(ident)
+}
+
+func (r *resolver) use(ident *ast.Identifier) {
+	symbol, ok := r.scope.resolve(ident.Value)
+	if !ok {
+		r.report(Error, ident, fmt.Sprintf("undefined identifier %s", ident.Value))
+		return
+	}
+
+	r.result.Uses[ident] = symbol
+}
+
+func (r *resolver) report(severity Severity, ident *ast.Identifier, message string) {
+	r.result.Diagnostics = append(r.result.Diagnostics, Diagnostic{
+		Severity: severity,
+		Line:     ident.Token.Line,
+		Column:   ident.Token.Column,
+		Message:  message,
+	})
+}
+
+func declaredBefore(a *ast.Identifier, b *ast.Identifier) bool {
+	if a.Token.Line != b.Token.Line {
+		return a.Token.Line < b.Token.Line
+	}
+	return a.Token.Column < b.Token.Column
+}
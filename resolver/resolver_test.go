@@ -0,0 +1,170 @@
+package resolver
+
+import (
+	"gomonkey/ast"
+	"gomonkey/lexer"
+	"gomonkey/parser"
+	"testing"
+)
+
+func resolve(t *testing.T, input string, builtins ...string) (*ast.Program, *Result) {
+	t.Helper()
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) > 0 {
+		t.Fatalf("parser has %d errors: %v", len(p.Errors()), p.Errors())
+	}
+
+	return program, Resolve(program, builtins...)
+}
+
+func usesOf(result *Result, name string) []*Symbol {
+	symbols := []*Symbol{}
+	for ident, symbol := range result.Uses {
+		if ident.Value == name {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
+func TestResolveScopes(t *testing.T) {
+	tests := []struct {
+		input         string
+		name          string
+		expectedScope SymbolScope
+		expectedIndex int
+	}{
+		{"let a = 1; a;", "a", GlobalScope, 0},
+		{"let a = 1; let b = 2; b;", "b", GlobalScope, 1},
+		{"fn(x) { x };", "x", LocalScope, 0},
+		{"fn(x, y) { let z = 1; z };", "z", LocalScope, 2},
+		{"fn(x) { fn() { x } };", "x", FreeScope, 0},
+		{"let g = 1; fn() { fn() { g } };", "g", GlobalScope, 0},
+		{"len;", "len", BuiltinScope, 1},
+		{"if (true) { let a = 1; a };", "a", GlobalScope, 0},
+		{"fn(xs) { for (i in xs) { i } };", "i", LocalScope, 1},
+		{"match (1) { [a, b] => b };", "b", GlobalScope, 1},
+		{"try { 1 } catch (e) { e };", "e", GlobalScope, 0},
+	}
+
+	for _, tt := range tests {
+		_, result := resolve(t, tt.input, "puts", "len")
+		if len(result.Diagnostics) != 0 {
+			t.Errorf("input %q: unexpected diagnostics: %v", tt.input, result.Diagnostics)
+			continue
+		}
+
+		symbols := usesOf(result, tt.name)
+		if len(symbols) != 1 {
+			t.Errorf("input %q: expected 1 use of %s. got=%d", tt.input, tt.name, len(symbols))
+			continue
+		}
+
+		if symbols[0].Scope != tt.expectedScope {
+			t.Errorf("input %q: %s has wrong scope. expected=%s, got=%s", tt.input, tt.name, tt.expectedScope, symbols[0].Scope)
+		}
+		if symbols[0].Index != tt.expectedIndex {
+			t.Errorf("input %q: %s has wrong index. expected=%d, got=%d", tt.input, tt.name, tt.expectedIndex, symbols[0].Index)
+		}
+	}
+}
+
+func TestResolveFreeSymbols(t *testing.T) {
+	program, result := resolve(t, "fn(a, b) { fn() { a + b + a } };")
+
+	outer := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	inner := outer.Body.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+
+	if len(result.Free[outer]) != 0 {
+		t.Errorf("outer function has free symbols: %v", result.Free[outer])
+	}
+
+	free := result.Free[inner]
+	if len(free) != 2 {
+		t.Fatalf("inner function has wrong number of free symbols. got=%d", len(free))
+	}
+
+	for i, name := range []string{"a", "b"} {
+		if free[i].Name != name || free[i].Scope != LocalScope || free[i].Index != i {
+			t.Errorf("free[%d] wrong. got=%+v", i, free[i])
+		}
+	}
+}
+
+func TestResolveDefinitionsAndUsesShareSymbols(t *testing.T) {
+	program, result := resolve(t, "let a = 1; a;")
+
+	let := program.Statements[0].(*ast.LetStatement)
+	use := program.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.Identifier)
+
+	if result.Definitions[let.Name] != result.Uses[use] {
+		t.Errorf("definition and use resolve to different symbols. definition=%+v, use=%+v",
+			result.Definitions[let.Name], result.Uses[use])
+	}
+	if result.Uses[use].Definition != let.Name {
+		t.Errorf("symbol has wrong definition. got=%v", result.Uses[use].Definition)
+	}
+}
+
+func TestResolveForwardReferencesInFunctions(t *testing.T) {
+	input := `
+let isEven = fn(n) { if (n == 0) { true } else { isOdd(n - 1) } };
+let isOdd = fn(n) { if (n == 0) { false } else { isEven(n - 1) } };
+`
+	_, result := resolve(t, input)
+	if len(result.Diagnostics) != 0 {
+		t.Errorf("unexpected diagnostics: %v", result.Diagnostics)
+	}
+}
+
+func TestResolveDiagnostics(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"a;", []string{"1:1: error: undefined identifier a"}},
+		{"a; let a = 1;", []string{"1:1: error: undefined identifier a"}},
+		{"let a = a;", []string{"1:9: error: undefined identifier a"}},
+		{"if (true) { let b = 1; }; b;", []string{"1:27: error: undefined identifier b"}},
+		{"fn(x, x) { x };", []string{"1:7: error: duplicate parameter x in function literal"}},
+		{"fn([x, {y: x}]) { x };", []string{"1:12: error: duplicate parameter x in function literal"}},
+		{"let x = 1; fn(x) { x };", []string{"1:15: warning: declaration of x shadows declaration at 1:5"}},
+		{"fn(x) { if (x) { let x = 2; x } };", []string{"1:22: warning: declaration of x shadows declaration at 1:4"}},
+		{"let f = fn(x) { x }; let x = 1;", []string{}},
+		{"let f = fn() { let y = 2; y }; let y = 1;", []string{}},
+		{"let a = 1; a.b; f(a, b: a);", []string{"1:17: error: undefined identifier f"}},
+		{"let {a, b: c} = 1; c; b;", []string{"1:23: error: undefined identifier b"}},
+		{"let [a = b, b = a] = 1;", []string{"1:10: error: undefined identifier b"}},
+		{"import \"m\" as m; export let a = m.b;", []string{}},
+	}
+
+	for _, tt := range tests {
+		_, result := resolve(t, tt.input)
+
+		if len(result.Diagnostics) != len(tt.expected) {
+			t.Errorf("input %q: wrong number of diagnostics. expected=%v, got=%v", tt.input, tt.expected, result.Diagnostics)
+			continue
+		}
+
+		for i, diagnostic := range result.Diagnostics {
+			if diagnostic.String() != tt.expected[i] {
+				t.Errorf("input %q: diagnostic %d wrong. expected=%q, got=%q", tt.input, i, tt.expected[i], diagnostic.String())
+			}
+		}
+	}
+}
+
+func TestResultErrors(t *testing.T) {
+	_, result := resolve(t, "let x = 1; fn(x) { y };")
+
+	if len(result.Diagnostics) != 2 {
+		t.Fatalf("wrong number of diagnostics. got=%v", result.Diagnostics)
+	}
+
+	errors := result.Errors()
+	if len(errors) != 1 || errors[0].Message != "undefined identifier y" {
+		t.Errorf("Errors returned wrong diagnostics. got=%v", errors)
+	}
+}
@@ -0,0 +1,113 @@
+package resolver
+
+import (
+	"fmt"
+	"gomonkey/ast"
+)
+
+type SymbolScope string
+
+const (
+	BuiltinScope SymbolScope = "BUILTIN"
+	GlobalScope  SymbolScope = "GLOBAL"
+	LocalScope   SymbolScope = "LOCAL"
+	FreeScope    SymbolScope = "FREE"
+)
+
+type Symbol struct {
+	Name       string
+	Scope      SymbolScope
+	Index      int
+	Definition *ast.Identifier
+}
+
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+)
+
+type Diagnostic struct {
+	Severity Severity
+	Line     int
+	Column   int
+	Message  string
+}
+
+func (diagnostic Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s", diagnostic.Line, diagnostic.Column, diagnostic.Severity, diagnostic.Message)
+}
+
+type function struct {
+	outer          *scope
+	global         bool
+	numDefinitions int
+	free           []*Symbol
+	freeSymbols    map[*Symbol]*Symbol
+	literal        *ast.FunctionLiteral
+}
+
+type scope struct {
+	outer    *scope
+	function *function
+	symbols  map[string]*Symbol
+	pending  []func()
+}
+
+func newScope(outer *scope, function *function) *scope {
+	return &scope{outer: outer, function: function, symbols: map[string]*Symbol{}}
+}
+
+func (s *scope) define(ident *ast.Identifier) *Symbol {
+	symbol := &Symbol{Name: ident.Value, Index: s.function.numDefinitions, Definition: ident}
+	if s.function.global {
+		symbol.Scope = GlobalScope
+	} else {
+		symbol.Scope = LocalScope
+	}
+
+	s.function.numDefinitions++
+	s.symbols[ident.Value] = symbol
+	return symbol
+}
+
+func (s *scope) resolve(name string) (*Symbol, bool) {
+	current := s
+	for ; current != nil && current.function == s.function; current = current.outer {
+		if symbol, ok := current.symbols[name]; ok {
+			return symbol, true
+		}
+	}
+
+	if current == nil {
+		return nil, false
+	}
+
+	symbol, ok := current.resolve(name)
+	if !ok || symbol.Scope == GlobalScope || symbol.Scope == BuiltinScope {
+		return symbol, ok
+	}
+
+	return s.function.defineFree(symbol), true
+}
+
+func (s *scope) lookupOuter(name string) (*Symbol, bool) {
+	for current := s.outer; current != nil; current = current.outer {
+		if symbol, ok := current.symbols[name]; ok && symbol.Scope != BuiltinScope {
+			return symbol, true
+		}
+	}
+	return nil, false
+}
+
+func (f *function) defineFree(original *Symbol) *Symbol {
+	if symbol, ok := f.freeSymbols[original]; ok {
+		return symbol
+	}
+
+	symbol := &Symbol{Name: original.Name, Scope: FreeScope, Index: len(f.free), Definition: original.Definition}
+	f.free = append(f.free, original)
+	f.freeSymbols[original] = symbol
+	return symbol
+}
@@ -5,34 +5,65 @@ type TokenType string
 type Token struct {
 	Type    TokenType
 	Literal string
+	Line    int
+	Column  int
 }
 
 const (
 	ILLEGAL = "ILLEGAL"
 	EOF     = "EOF"
+	COMMENT = "COMMENT"
 
-	IDENT = "IDENT"
-	INT   = "INT"
+	IDENT  = "IDENT"
+	INT    = "INT"
+	STRING = "STRING"
+
+	ASSIGN          = "="
+	PLUS_ASSIGN     = "+="
+	MINUS_ASSIGN    = "-="
+	ASTERISK_ASSIGN = "*="
+	SLASH_ASSIGN    = "/="
 
-	ASSIGN   = "="
 	PLUS     = "+"
 	MINUS    = "-"
 	ASTERISK = "*"
 	SLASH    = "/"
+	PERCENT  = "%"
 	LT       = "<"
 	GT       = ">"
 	BANG     = "!"
 
+	AMPERSAND = "&"
+	PIPE      = "|"
+	CARET     = "^"
+	TILDE     = "~"
+
 	EQUAL     = "=="
 	NOT_EQUAL = "!="
+	LT_EQUAL  = "<="
+	GT_EQUAL  = ">="
+	AND       = "&&"
+	OR        = "||"
+
+	LEFT_SHIFT  = "<<"
+	RIGHT_SHIFT = ">>"
+	POWER       = "**"
 
 	COMMA     = ","
 	SEMICOLON = ";"
+	COLON     = ":"
+	QUESTION  = "?"
+	FAT_ARROW = "=>"
+	ARROW     = "->"
+	ELLIPSIS  = "..."
+	DOT       = "."
 
 	OPEN_PARENTHESIS  = "("
 	CLOSE_PARENTHESIS = ")"
 	OPEN_CURLY        = "{"
 	CLOSE_CURLY       = "}"
+	OPEN_BRACKET      = "["
+	CLOSE_BRACKET     = "]"
 
 	FUNCTION = "FUNCTION"
 	LET      = "LET"
@@ -41,16 +72,42 @@ const (
 	IF       = "IF"
 	ELSE     = "ELSE"
 	RETURN   = "RETURN"
+	WHILE    = "WHILE"
+	FOR      = "FOR"
+	IN       = "IN"
+	BREAK    = "BREAK"
+	CONTINUE = "CONTINUE"
+	MATCH    = "MATCH"
+	THROW    = "THROW"
+	TRY      = "TRY"
+	CATCH    = "CATCH"
+	FINALLY  = "FINALLY"
+	IMPORT   = "IMPORT"
+	EXPORT   = "EXPORT"
+	AS       = "AS"
 )
 
 var keywords = map[string]TokenType{
-	"fn":     FUNCTION,
-	"let":    LET,
-	"true":   TRUE,
-	"false":  FALSE,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
+	"fn":       FUNCTION,
+	"let":      LET,
+	"true":     TRUE,
+	"false":    FALSE,
+	"if":       IF,
+	"else":     ELSE,
+	"return":   RETURN,
+	"while":    WHILE,
+	"for":      FOR,
+	"in":       IN,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"match":    MATCH,
+	"throw":    THROW,
+	"try":      TRY,
+	"catch":    CATCH,
+	"finally":  FINALLY,
+	"import":   IMPORT,
+	"export":   EXPORT,
+	"as":       AS,
 }
 
 func LookupIndent(ident string) TokenType {
@@ -0,0 +1,496 @@
+package types
+
+import (
+	"fmt"
+	"gomonkey/ast"
+	"gomonkey/resolver"
+	"gomonkey/token"
+	"sort"
+)
+
+type Diagnostic struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (diagnostic Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s", diagnostic.Line, diagnostic.Column, diagnostic.Message)
+}
+
+type Result struct {
+	Types       map[ast.Expression]Type
+	Diagnostics []Diagnostic
+}
+
+type function struct {
+	declared Type
+	returns  []Type
+}
+
+type checker struct {
+	resolved  *resolver.Result
+	bindings  map[*ast.Identifier]Type
+	functions []*function
+	result    *Result
+}
+
+func Check(program *ast.Program) *Result {
+	c := &checker{
+		resolved: resolver.Resolve(program),
+		bindings: map[*ast.Identifier]Type{},
+		result: &Result{
+			Types:       map[ast.Expression]Type{},
+			Diagnostics: []Diagnostic{},
+		},
+	}
+
+	c.statements(program.Statements)
+
+	sort.SliceStable(c.result.Diagnostics, func(i, j int) bool {
+		a, b := c.result.Diagnostics[i], c.result.Diagnostics[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	return c.result
+}
+
+func (c *checker) statements(statements []ast.Statement) Type {
+	var last Type = Any
+	for _, statement := range statements {
+		last = c.statement(statement)
+	}
+	return last
+}
+
+func (c *checker) statement(statement ast.Statement) Type {
+	switch statement := statement.(type) {
+	case *ast.LetStatement:
+		c.let(statement)
+	case *ast.ExportStatement:
+		c.let(statement.Statement)
+	case *ast.ReturnStatement:
+		c.returnValue(statement.ReturnValue, statement.Token)
+	case *ast.ThrowStatement:
+		c.expression(statement.Value)
+	case *ast.ExpressionStatement:
+		return c.expression(statement.Expression)
+	case *ast.BlockStatement:
+		return c.block(statement)
+	case *ast.WhileStatement:
+		c.expression(statement.Condition)
+		c.block(statement.Body)
+	case *ast.ForStatement:
+		if statement.Init != nil {
+			c.statement(statement.Init)
+		}
+		c.expression(statement.Condition)
+		c.expression(statement.Update)
+		c.block(statement.Body)
+	case *ast.ForInStatement:
+		c.expression(statement.Iterable)
+		c.block(statement.Body)
+	}
+	return Any
+}
+
+func (c *checker) block(block *ast.BlockStatement) Type {
+	if block == nil {
+		return Any
+	}
+	return c.statements(block.Statements)
+}
+
+func (c *checker) let(statement *ast.LetStatement) {
+	if statement == nil {
+		return
+	}
+
+	if statement.Name == nil {
+		c.expression(statement.Value)
+		return
+	}
+
+	var declared Type
+	if statement.Type != nil {
+		declared = c.typeOf(statement.Type)
+		c.bindings[statement.Name] = declared
+	} else if literal, ok := statement.Value.(*ast.FunctionLiteral); ok {
+		c.bindings[statement.Name] = c.signature(literal)
+	}
+
+	value := c.expression(statement.Value)
+	if declared == nil {
+		c.bindings[statement.Name] = value
+		return
+	}
+
+	if !AssignableTo(value, declared) {
+		c.errorf(statement.Name.Token, "cannot use %s (type %s) as %s in let %s",
+			statement.Value.String(), value, declared, statement.Name.Value)
+	}
+}
+
+func (c *checker) returnValue(expression ast.Expression, tok token.Token) {
+	value := c.expression(expression)
+	if len(c.functions) == 0 {
+		return
+	}
+
+	function := c.functions[len(c.functions)-1]
+	function.returns = append(function.returns, value)
+
+	if function.declared != nil && !AssignableTo(value, function.declared) {
+		c.errorf(tok, "cannot use %s (type %s) as %s in return", expression.String(), value, function.declared)
+	}
+}
+
+func (c *checker) expression(expression ast.Expression) Type {
+	if expression == nil {
+		return Any
+	}
+
+	typ := c.infer(expression)
+	c.result.Types[expression] = typ
+	return typ
+}
+
+func (c *checker) infer(expression ast.Expression) Type {
+	switch expression := expression.(type) {
+	case *ast.IntegerLiteral:
+		return Int
+	case *ast.StringLiteral:
+		return String
+	case *ast.Boolean:
+		return Bool
+	case *ast.Identifier:
+		return c.identifier(expression)
+	case *ast.PrefixExpression:
+		return c.prefix(expression)
+	case *ast.GroupedExpression:
+		return c.expression(expression.Expression)
+	case *ast.InfixExpression:
+		return c.infix(expression, expression.Token, expression.Operator, c.expression(expression.Left), c.expression(expression.Right))
+	case *ast.AssignExpression:
+		return c.assign(expression)
+	case *ast.IfExpression:
+		c.expression(expression.Condition)
+		consequence := c.block(expression.Consequence)
+		if expression.Alternative == nil {
+			return Any
+		}
+		return join([]Type{consequence, c.block(expression.Alternative)})
+	case *ast.ConditionalExpression:
+		c.expression(expression.Condition)
+		return join([]Type{c.expression(expression.Consequence), c.expression(expression.Alternative)})
+	case *ast.FunctionLiteral:
+		return c.functionLiteral(expression)
+	case *ast.CallExpression:
+		return c.call(expression)
+	case *ast.MemberExpression:
+		c.expression(expression.Object)
+	case *ast.IndexExpression:
+		c.expression(expression.Left)
+		c.expression(expression.Index)
+	case *ast.SpreadExpression:
+		c.expression(expression.Value)
+	case *ast.NamedArgument:
+		c.expression(expression.Value)
+	case *ast.MatchExpression:
+		c.expression(expression.Subject)
+		arms := []Type{}
+		for _, arm := range expression.Arms {
+			c.expression(arm.Guard)
+			arms = append(arms, c.expression(arm.Body))
+		}
+		return join(arms)
+	case *ast.TryExpression:
+		block := c.block(expression.Block)
+		if expression.Catch == nil {
+			c.block(expression.Finally)
+			return block
+		}
+		catch := c.block(expression.Catch)
+		c.block(expression.Finally)
+		return join([]Type{block, catch})
+	}
+	return Any
+}
+
+func (c *checker) identifier(ident *ast.Identifier) Type {
+	symbol, ok := c.resolved.Uses[ident]
+	if !ok || symbol.Definition == nil {
+		return Any
+	}
+
+	if typ, ok := c.bindings[symbol.Definition]; ok {
+		return typ
+	}
+	return Any
+}
+
+func (c *checker) prefix(expression *ast.PrefixExpression) Type {
+	right := c.expression(expression.Right)
+
+	if expression.Operator == token.BANG {
+		return Bool
+	}
+
+	if known(right) && right != Int {
+		c.errorf(expression.Token, "invalid operation: %s (operator %s not defined on %s)",
+			expression.String(), expression.Operator, right)
+	}
+	return Int
+}
+
+func (c *checker) infix(expression ast.Expression, tok token.Token, operator string, left, right Type) Type {
+	if known(left) && known(right) && !Identical(left, right) && operator != token.EQUAL && operator != token.NOT_EQUAL {
+		c.errorf(tok, "invalid operation: %s (mismatched types %s and %s)", expression.String(), left, right)
+		return Any
+	}
+
+	operand := left
+	if !known(operand) {
+		operand = right
+	}
+
+	switch operator {
+	case token.EQUAL, token.NOT_EQUAL:
+		return Bool
+	case token.AND, token.OR:
+		if operand == Bool {
+			return Bool
+		}
+		return Any
+	case token.PLUS:
+		return c.operand(expression, tok, operator, operand, Int, String)
+	case token.LT, token.GT, token.LT_EQUAL, token.GT_EQUAL:
+		c.operand(expression, tok, operator, operand, Int, String)
+		return Bool
+	default:
+		return c.operand(expression, tok, operator, operand, Int)
+	}
+}
+
+func (c *checker) operand(expression ast.Expression, tok token.Token, operator string, operand Type, allowed ...Type) Type {
+	if !known(operand) {
+		return Any
+	}
+
+	for _, typ := range allowed {
+		if operand == typ {
+			return operand
+		}
+	}
+
+	c.errorf(tok, "invalid operation: %s (operator %s not defined on %s)", expression.String(), operator, operand)
+	return Any
+}
+
+func (c *checker) assign(expression *ast.AssignExpression) Type {
+	target := c.expression(expression.Target)
+	value := c.expression(expression.Value)
+
+	if expression.Operator == token.ASSIGN {
+		if !AssignableTo(value, target) {
+			c.errorf(expression.Token, "cannot use %s (type %s) as %s in assignment", expression.Value.String(), value, target)
+		}
+		return target
+	}
+
+	operator := expression.Operator[:len(expression.Operator)-1]
+	c.infix(expression, expression.Token, operator, target, value)
+	return target
+}
+
+func (c *checker) signature(literal *ast.FunctionLiteral) *Function {
+	signature := &Function{Parameters: []Type{}, Return: Any}
+	if literal.ReturnType != nil {
+		signature.Return = c.typeOf(literal.ReturnType)
+	}
+
+	optional := false
+	for _, parameter := range literal.Parameters {
+		switch parameter := parameter.(type) {
+		case *ast.RestPattern:
+			signature.Variadic = true
+			continue
+		case *ast.DefaultPattern:
+			optional = true
+			signature.Parameters = append(signature.Parameters, c.parameterType(parameter.Target))
+		default:
+			signature.Parameters = append(signature.Parameters, c.parameterType(parameter))
+		}
+
+		if !optional {
+			signature.Required++
+		}
+	}
+
+	return signature
+}
+
+func (c *checker) parameterType(pattern ast.Pattern) Type {
+	if ident, ok := pattern.(*ast.Identifier); ok && ident.Type != nil {
+		return c.typeOf(ident.Type)
+	}
+	return Any
+}
+
+func (c *checker) functionLiteral(literal *ast.FunctionLiteral) Type {
+	signature := c.signature(literal)
+
+	for _, parameter := range literal.Parameters {
+		target := parameter
+		if withDefault, ok := parameter.(*ast.DefaultPattern); ok {
+			target = withDefault.Target
+			value := c.expression(withDefault.Default)
+			declared := c.parameterType(target)
+			if !AssignableTo(value, declared) {
+				c.errorf(withDefault.Token, "cannot use %s (type %s) as %s in default value",
+					withDefault.Default.String(), value, declared)
+			}
+		}
+
+		if ident, ok := target.(*ast.Identifier); ok {
+			c.bindings[ident] = c.parameterType(ident)
+		}
+	}
+
+	function := &function{}
+	if literal.ReturnType != nil {
+		function.declared = signature.Return
+	}
+
+	c.functions = append(c.functions, function)
+	statements := literal.Body.Statements
+	for i, statement := range statements {
+		if last, ok := statement.(*ast.ExpressionStatement); ok && i == len(statements)-1 {
+			c.returnValue(last.Expression, last.Token)
+			continue
+		}
+		c.statement(statement)
+	}
+	c.functions = c.functions[:len(c.functions)-1]
+
+	if literal.ReturnType == nil {
+		signature.Return = join(function.returns)
+	}
+	return signature
+}
+
+func (c *checker) call(expression *ast.CallExpression) Type {
+	callee := c.expression(expression.Function)
+
+	arguments := []Type{}
+	checkable := true
+	for _, argument := range expression.Arguments {
+		arguments = append(arguments, c.expression(argument))
+		switch argument.(type) {
+		case *ast.SpreadExpression, *ast.NamedArgument:
+			checkable = false
+		}
+	}
+
+	function, ok := callee.(*Function)
+	if !ok {
+		if known(callee) {
+			c.errorf(tokenOf(expression.Function), "cannot call non-function %s (type %s)", expression.Function.String(), callee)
+		}
+		return Any
+	}
+
+	if !checkable {
+		return function.Return
+	}
+
+	name := expression.Function.String()
+	switch {
+	case len(arguments) < function.Required:
+		c.errorf(expression.Token, "not enough arguments in call to %s (have %d, want %d)", name, len(arguments), function.Required)
+	case len(arguments) > len(function.Parameters) && !function.Variadic:
+		c.errorf(expression.Token, "too many arguments in call to %s (have %d, want %d)", name, len(arguments), len(function.Parameters))
+	}
+
+	for i, argument := range arguments {
+		if i >= len(function.Parameters) {
+			break
+		}
+		if !AssignableTo(argument, function.Parameters[i]) {
+			c.errorf(tokenOf(expression.Arguments[i]), "cannot use %s (type %s) as %s in argument to %s",
+				expression.Arguments[i].String(), argument, function.Parameters[i], name)
+		}
+	}
+
+	return function.Return
+}
+
+func (c *checker) typeOf(expression ast.TypeExpression) Type {
+	switch expression := expression.(type) {
+	case *ast.NamedType:
+		if typ, ok := basics[expression.Name]; ok {
+			return typ
+		}
+		c.errorf(expression.Token, "unknown type %s", expression.Name)
+	case *ast.FunctionType:
+		function := &Function{Parameters: []Type{}, Required: len(expression.Parameters), Return: c.typeOf(expression.Return)}
+		for _, parameter := range expression.Parameters {
+			function.Parameters = append(function.Parameters, c.typeOf(parameter))
+		}
+		return function
+	}
+	return Any
+}
+
+func (c *checker) errorf(tok token.Token, format string, args ...interface{}) {
+	c.result.Diagnostics = append(c.result.Diagnostics, Diagnostic{
+		Line:    tok.Line,
+		Column:  tok.Column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func tokenOf(expression ast.Expression) token.Token {
+	switch expression := expression.(type) {
+	case *ast.Identifier:
+		return expression.Token
+	case *ast.IntegerLiteral:
+		return expression.Token
+	case *ast.StringLiteral:
+		return expression.Token
+	case *ast.Boolean:
+		return expression.Token
+	case *ast.PrefixExpression:
+		return expression.Token
+	case *ast.GroupedExpression:
+		return tokenOf(expression.Expression)
+	case *ast.InfixExpression:
+		return tokenOf(expression.Left)
+	case *ast.AssignExpression:
+		return tokenOf(expression.Target)
+	case *ast.ConditionalExpression:
+		return tokenOf(expression.Condition)
+	case *ast.CallExpression:
+		return tokenOf(expression.Function)
+	case *ast.MemberExpression:
+		return tokenOf(expression.Object)
+	case *ast.IndexExpression:
+		return tokenOf(expression.Left)
+	case *ast.IfExpression:
+		return expression.Token
+	case *ast.FunctionLiteral:
+		return expression.Token
+	case *ast.MatchExpression:
+		return expression.Token
+	case *ast.TryExpression:
+		return expression.Token
+	case *ast.SpreadExpression:
+		return expression.Token
+	case *ast.NamedArgument:
+		return expression.Token
+	}
+	return token.Token{}
+}
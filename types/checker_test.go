@@ -0,0 +1,133 @@
+package types
+
+import (
+	"gomonkey/ast"
+	"gomonkey/lexer"
+	"gomonkey/parser"
+	"strings"
+	"testing"
+)
+
+func check(t *testing.T, input string) (*ast.Program, *Result) {
+	t.Helper()
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) > 0 {
+		t.Fatalf("parser has %d errors: %v", len(p.Errors()), p.Errors())
+	}
+
+	return program, Check(program)
+}
+
+func TestInferredTypes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"5", "int"},
+		{`"a" + "b"`, "string"},
+		{"1 < 2", "bool"},
+		{"!5", "bool"},
+		{"-5", "int"},
+		{"let x = 5; x", "int"},
+		{"let x: any = 5; x", "any"},
+		{"fn(a: int, b: int) { a + b }", "fn(int, int) -> int"},
+		{"fn(a, b: string = \"x\", ...rest) -> bool { true }", "fn(any, string, ...) -> bool"},
+		{"fn(x) { if (x) { return 1; } 2 }", "fn(any) -> int"},
+		{"fn(x) { if (x) { return 1; } \"a\" }", "fn(any) -> any"},
+		{"let add = fn(a: int, b: int) { a + b }; add(1, 2)", "int"},
+		{"if (x) { 1 } else { 2 }", "int"},
+		{"if (x) { 1 } else { \"a\" }", "any"},
+		{"x ? \"a\" : \"b\"", "string"},
+		{"let f: fn(int) -> string = g; f(1)", "string"},
+		{"let n = 1; fn() { n }", "fn() -> int"},
+	}
+
+	for _, tt := range tests {
+		program, result := check(t, tt.input)
+
+		statements := program.Statements
+		last := statements[len(statements)-1].(*ast.ExpressionStatement)
+
+		typ, ok := result.Types[last.Expression]
+		if !ok {
+			t.Errorf("input %q: no type recorded for %s", tt.input, last.Expression.String())
+			continue
+		}
+		if typ.String() != tt.expected {
+			t.Errorf("input %q: wrong type. expected=%s, got=%s", tt.input, tt.expected, typ)
+		}
+	}
+}
+
+func TestCheckDiagnostics(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{`"a" + 1;`, []string{`1:5: invalid operation: ("a" + 1) (mismatched types string and int)`}},
+		{`true - false;`, []string{`1:6: invalid operation: (true - false) (operator - not defined on bool)`}},
+		{`-"a";`, []string{`1:1: invalid operation: (-"a") (operator - not defined on string)`}},
+		{`1 == "a"; x + 1; "a" < "b";`, []string{}},
+		{`let x: int = "a";`, []string{`1:5: cannot use "a" (type string) as int in let x`}},
+		{`let x: int = 1; x = "a";`, []string{`1:19: cannot use "a" (type string) as int in assignment`}},
+		{`let x = "a"; x -= 1;`, []string{`1:16: invalid operation: (x -= 1) (mismatched types string and int)`}},
+		{`let x = 5; x(1);`, []string{`1:12: cannot call non-function x (type int)`}},
+		{`"f"();`, []string{`1:1: cannot call non-function "f" (type string)`}},
+		{`let f = fn(a: int) { a }; f("a");`, []string{`1:29: cannot use "a" (type string) as int in argument to f`}},
+		{`let f = fn(a, b = 1) { a }; f(); f(1, 2, 3);`, []string{
+			`1:30: not enough arguments in call to f (have 0, want 1)`,
+			`1:35: too many arguments in call to f (have 3, want 2)`,
+		}},
+		{`let f = fn(a, ...rest) { a }; f(1, 2, 3); f(...xs); f(a: 1);`, []string{}},
+		{`fn() -> int { return "a"; };`, []string{`1:15: cannot use "a" (type string) as int in return`}},
+		{`fn() -> int { "a" };`, []string{`1:15: cannot use "a" (type string) as int in return`}},
+		{`fn(a: int = "x") { a };`, []string{`1:11: cannot use "x" (type string) as int in default value`}},
+		{`let x: number = 1;`, []string{`1:8: unknown type number`}},
+		{`let f = fn(n: int) -> int { f("a") };`, []string{`1:31: cannot use "a" (type string) as int in argument to f`}},
+		{`let f: fn(int) -> int = fn(a: string) -> int { 1 };`, []string{
+			`1:5: cannot use fn(a: string) -> int 1 (type fn(string) -> int) as fn(int) -> int in let f`,
+		}},
+	}
+
+	for _, tt := range tests {
+		_, result := check(t, tt.input)
+
+		actual := []string{}
+		for _, diagnostic := range result.Diagnostics {
+			actual = append(actual, diagnostic.String())
+		}
+
+		if strings.Join(actual, "\n") != strings.Join(tt.expected, "\n") {
+			t.Errorf("input %q: wrong diagnostics.\nexpected=%q\ngot=%q", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+func TestAssignableTo(t *testing.T) {
+	intToBool := &Function{Parameters: []Type{Int}, Required: 1, Return: Bool}
+	anyToBool := &Function{Parameters: []Type{Any}, Required: 1, Return: Bool}
+	stringToBool := &Function{Parameters: []Type{String}, Required: 1, Return: Bool}
+
+	tests := []struct {
+		value    Type
+		target   Type
+		expected bool
+	}{
+		{Int, Int, true},
+		{Int, String, false},
+		{Any, String, true},
+		{Int, Any, true},
+		{intToBool, intToBool, true},
+		{anyToBool, intToBool, true},
+		{stringToBool, intToBool, false},
+		{intToBool, Int, false},
+	}
+
+	for i, tt := range tests {
+		if AssignableTo(tt.value, tt.target) != tt.expected {
+			t.Errorf("tests[%d]: AssignableTo(%s, %s) wrong. expected=%t", i, tt.value, tt.target, tt.expected)
+		}
+	}
+}
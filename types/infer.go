@@ -0,0 +1,724 @@
+package types
+
+import (
+	"fmt"
+	"gomonkey/ast"
+	"gomonkey/token"
+	"sort"
+)
+
+type Variable struct {
+	Name string
+}
+
+func (variable *Variable) String() string {
+	return "'" + variable.Name
+}
+
+type Binding struct {
+	Name   string
+	Type   Type
+	Line   int
+	Column int
+}
+
+type Inference struct {
+	Bindings    []Binding
+	Diagnostics []Diagnostic
+}
+
+type term interface{}
+
+type tvar struct {
+	id int
+}
+
+type tcon struct {
+	name     string
+	args     []term
+	required int
+	variadic bool
+	origin   token.Token
+}
+
+type scheme struct {
+	vars []int
+	body term
+}
+
+type env struct {
+	outer   *env
+	schemes map[string]*scheme
+}
+
+func (e *env) lookup(name string) (*scheme, bool) {
+	for current := e; current != nil; current = current.outer {
+		if s, ok := current.schemes[name]; ok {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+type inferrer struct {
+	next      int
+	subst     map[int]term
+	env       *env
+	functions []term
+	result    *Inference
+}
+
+func Infer(program *ast.Program) *Inference {
+	in := &inferrer{
+		subst:  map[int]term{},
+		env:    &env{schemes: map[string]*scheme{}},
+		result: &Inference{Bindings: []Binding{}, Diagnostics: []Diagnostic{}},
+	}
+
+	for _, statement := range program.Statements {
+		in.statement(statement)
+
+		if export, ok := statement.(*ast.ExportStatement); ok {
+			statement = export.Statement
+		}
+		if let, ok := statement.(*ast.LetStatement); ok && let != nil {
+			for _, name := range letNames(let) {
+				in.bindingOf(name)
+			}
+		}
+	}
+
+	sort.SliceStable(in.result.Diagnostics, func(i, j int) bool {
+		a, b := in.result.Diagnostics[i], in.result.Diagnostics[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	return in.result
+}
+
+func (in *inferrer) bindingOf(name *ast.Identifier) {
+	s, ok := in.env.schemes[name.Value]
+	if !ok {
+		return
+	}
+
+	in.result.Bindings = append(in.result.Bindings, Binding{
+		Name:   name.Value,
+		Type:   in.export(s.body, map[int]string{}),
+		Line:   name.Token.Line,
+		Column: name.Token.Column,
+	})
+}
+
+func (in *inferrer) fresh() term {
+	in.next++
+	return &tvar{id: in.next}
+}
+
+func (in *inferrer) basic(name string, origin token.Token) term {
+	return &tcon{name: name, origin: origin}
+}
+
+func (in *inferrer) openScope() {
+	in.env = &env{outer: in.env, schemes: map[string]*scheme{}}
+}
+
+func (in *inferrer) closeScope() {
+	in.env = in.env.outer
+}
+
+func (in *inferrer) bind(name string, t term) {
+	in.env.schemes[name] = &scheme{body: t}
+}
+
+func (in *inferrer) statements(statements []ast.Statement) term {
+	var last term = in.fresh()
+	for _, statement := range statements {
+		last = in.statement(statement)
+	}
+	return last
+}
+
+func (in *inferrer) block(block *ast.BlockStatement) term {
+	if block == nil {
+		return in.fresh()
+	}
+
+	in.openScope()
+	defer in.closeScope()
+	return in.statements(block.Statements)
+}
+
+func (in *inferrer) statement(statement ast.Statement) term {
+	switch statement := statement.(type) {
+	case *ast.LetStatement:
+		in.let(statement)
+	case *ast.ExportStatement:
+		in.let(statement.Statement)
+	case *ast.ImportStatement:
+		in.bind(statement.Alias.Value, in.fresh())
+	case *ast.ReturnStatement:
+		in.returnValue(statement.ReturnValue, statement.Token)
+	case *ast.ThrowStatement:
+		in.expression(statement.Value)
+	case *ast.ExpressionStatement:
+		return in.expression(statement.Expression)
+	case *ast.BlockStatement:
+		return in.block(statement)
+	case *ast.WhileStatement:
+		in.expression(statement.Condition)
+		in.block(statement.Body)
+	case *ast.ForStatement:
+		in.openScope()
+		if statement.Init != nil {
+			in.statement(statement.Init)
+		}
+		if statement.Condition != nil {
+			in.expression(statement.Condition)
+		}
+		in.expression(statement.Update)
+		in.block(statement.Body)
+		in.closeScope()
+	case *ast.ForInStatement:
+		in.expression(statement.Iterable)
+		in.openScope()
+		in.bind(statement.Variable.Value, in.fresh())
+		in.block(statement.Body)
+		in.closeScope()
+	}
+	return in.fresh()
+}
+
+func (in *inferrer) let(statement *ast.LetStatement) {
+	if statement == nil {
+		return
+	}
+
+	if statement.Name == nil {
+		in.expression(statement.Value)
+		in.pattern(statement.Pattern)
+		return
+	}
+
+	literal, isFunction := statement.Value.(*ast.FunctionLiteral)
+	if !isFunction {
+		value := in.expression(statement.Value)
+		in.annotate(value, statement.Type, statement.Name.Token)
+		in.bind(statement.Name.Value, value)
+		return
+	}
+
+	self := in.fresh()
+	in.openScope()
+	in.bind(statement.Name.Value, self)
+	value := in.functionLiteral(literal)
+	in.closeScope()
+
+	in.unify(self, value, literal.Token)
+	in.annotate(value, statement.Type, statement.Name.Token)
+	in.env.schemes[statement.Name.Value] = in.generalize(value)
+}
+
+func (in *inferrer) annotate(value term, annotation ast.TypeExpression, at token.Token) {
+	if annotation != nil {
+		in.unify(in.annotation(annotation), value, at)
+	}
+}
+
+func (in *inferrer) returnValue(expression ast.Expression, at token.Token) {
+	value := in.expression(expression)
+	if expression == nil {
+		value = in.basic("null", at)
+	}
+	if len(in.functions) > 0 {
+		in.unify(in.functions[len(in.functions)-1], value, at)
+	}
+}
+
+func (in *inferrer) expression(expression ast.Expression) term {
+	switch expression := expression.(type) {
+	case *ast.IntegerLiteral:
+		return in.basic("int", expression.Token)
+	case *ast.StringLiteral:
+		return in.basic("string", expression.Token)
+	case *ast.Boolean:
+		return in.basic("bool", expression.Token)
+	case *ast.Identifier:
+		if s, ok := in.env.lookup(expression.Value); ok {
+			return in.instantiate(s)
+		}
+	case *ast.PrefixExpression:
+		return in.prefix(expression)
+	case *ast.GroupedExpression:
+		return in.expression(expression.Expression)
+	case *ast.InfixExpression:
+		return in.infix(expression)
+	case *ast.AssignExpression:
+		target := in.expression(expression.Target)
+		in.unify(target, in.expression(expression.Value), expression.Token)
+		if expression.Operator != token.ASSIGN && expression.Operator != token.PLUS_ASSIGN {
+			in.unify(in.basic("int", expression.Token), target, expression.Token)
+		}
+		return target
+	case *ast.IfExpression:
+		in.expression(expression.Condition)
+		consequence := in.block(expression.Consequence)
+		if expression.Alternative == nil {
+			return in.fresh()
+		}
+		in.unify(consequence, in.block(expression.Alternative), expression.Token)
+		return consequence
+	case *ast.ConditionalExpression:
+		in.expression(expression.Condition)
+		consequence := in.expression(expression.Consequence)
+		in.unify(consequence, in.expression(expression.Alternative), expression.Token)
+		return consequence
+	case *ast.FunctionLiteral:
+		return in.functionLiteral(expression)
+	case *ast.CallExpression:
+		return in.call(expression)
+	case *ast.MemberExpression:
+		in.expression(expression.Object)
+	case *ast.IndexExpression:
+		in.expression(expression.Left)
+		in.expression(expression.Index)
+	case *ast.SpreadExpression:
+		in.expression(expression.Value)
+	case *ast.NamedArgument:
+		in.expression(expression.Value)
+	case *ast.MatchExpression:
+		return in.match(expression)
+	case *ast.TryExpression:
+		block := in.block(expression.Block)
+		if expression.Catch != nil {
+			in.openScope()
+			in.bind(expression.CatchParameter.Value, in.fresh())
+			in.unify(block, in.block(expression.Catch), expression.Token)
+			in.closeScope()
+		}
+		in.block(expression.Finally)
+		return block
+	}
+	return in.fresh()
+}
+
+func (in *inferrer) prefix(expression *ast.PrefixExpression) term {
+	right := in.expression(expression.Right)
+
+	if expression.Operator == token.BANG {
+		return in.basic("bool", expression.Token)
+	}
+
+	in.unify(in.basic("int", expression.Token), right, expression.Token)
+	return in.basic("int", expression.Token)
+}
+
+func (in *inferrer) infix(expression *ast.InfixExpression) term {
+	left := in.expression(expression.Left)
+	right := in.expression(expression.Right)
+	at := expression.Token
+
+	switch expression.Operator {
+	case token.PLUS:
+		if !in.unify(left, right, at) {
+			return left
+		}
+		switch operand := in.prune(left).(type) {
+		case *tvar:
+			in.unify(in.basic("int", at), operand, at)
+		case *tcon:
+			if operand.name != "int" && operand.name != "string" {
+				in.errorf(at, "invalid operation: %s (operator %s not defined on %s)", expression.String(), expression.Operator, in.display(operand))
+			}
+		}
+		return left
+	case token.EQUAL, token.NOT_EQUAL, token.LT, token.GT, token.LT_EQUAL, token.GT_EQUAL:
+		in.unify(left, right, at)
+		return in.basic("bool", at)
+	case token.AND, token.OR:
+		in.unify(left, right, at)
+		return left
+	default:
+		in.unify(in.basic("int", at), left, at)
+		in.unify(in.basic("int", at), right, at)
+		return in.basic("int", at)
+	}
+}
+
+func (in *inferrer) functionLiteral(literal *ast.FunctionLiteral) term {
+	function := &tcon{name: "fn", args: []term{}, origin: literal.Token}
+
+	in.openScope()
+	defer in.closeScope()
+
+	optional := false
+	for _, parameter := range literal.Parameters {
+		var ident *ast.Identifier
+		typ := in.fresh()
+
+		switch parameter := parameter.(type) {
+		case *ast.RestPattern:
+			function.variadic = true
+			in.bind(parameter.Name.Value, in.fresh())
+			continue
+		case *ast.DefaultPattern:
+			optional = true
+			in.unify(typ, in.expression(parameter.Default), parameter.Token)
+			ident, _ = parameter.Target.(*ast.Identifier)
+			if ident == nil {
+				in.pattern(parameter.Target)
+			}
+		case *ast.Identifier:
+			ident = parameter
+		default:
+			in.pattern(parameter)
+		}
+
+		if ident != nil {
+			in.annotate(typ, ident.Type, ident.Token)
+			in.bind(ident.Value, typ)
+		}
+
+		function.args = append(function.args, typ)
+		if !optional {
+			function.required++
+		}
+	}
+
+	result := in.fresh()
+	if literal.ReturnType != nil {
+		in.unify(in.annotation(literal.ReturnType), result, typeToken(literal.ReturnType))
+	}
+	function.args = append(function.args, result)
+
+	in.functions = append(in.functions, result)
+	statements := literal.Body.Statements
+	for i, statement := range statements {
+		if last, ok := statement.(*ast.ExpressionStatement); ok && i == len(statements)-1 {
+			in.returnValue(last.Expression, tokenOf(last.Expression))
+			continue
+		}
+		in.statement(statement)
+	}
+	if len(statements) == 0 || !endsWithValue(statements[len(statements)-1]) {
+		in.unify(result, in.basic("null", literal.Token), literal.Token)
+	}
+	in.functions = in.functions[:len(in.functions)-1]
+
+	return function
+}
+
+func (in *inferrer) call(expression *ast.CallExpression) term {
+	callee := in.expression(expression.Function)
+
+	arguments := []term{}
+	checkable := true
+	for _, argument := range expression.Arguments {
+		arguments = append(arguments, in.expression(argument))
+		switch argument.(type) {
+		case *ast.SpreadExpression, *ast.NamedArgument:
+			checkable = false
+		}
+	}
+
+	if !checkable {
+		return in.fresh()
+	}
+
+	result := in.fresh()
+	if function, ok := in.prune(callee).(*tcon); ok && function.name == "fn" {
+		parameters := function.args[:len(function.args)-1]
+		name := expression.Function.String()
+
+		switch {
+		case len(arguments) < function.required:
+			in.errorf(expression.Token, "not enough arguments in call to %s (have %d, want %d)", name, len(arguments), function.required)
+			return function.args[len(function.args)-1]
+		case len(arguments) > len(parameters) && !function.variadic:
+			in.errorf(expression.Token, "too many arguments in call to %s (have %d, want %d)", name, len(arguments), len(parameters))
+			return function.args[len(function.args)-1]
+		}
+
+		for i, argument := range arguments {
+			if i < len(parameters) {
+				in.unify(parameters[i], argument, tokenOf(expression.Arguments[i]))
+			}
+		}
+		return function.args[len(function.args)-1]
+	}
+
+	expected := &tcon{name: "fn", args: append(arguments, result), required: len(arguments), origin: expression.Token}
+	in.unify(callee, expected, expression.Token)
+	return result
+}
+
+func (in *inferrer) match(expression *ast.MatchExpression) term {
+	subject := in.expression(expression.Subject)
+	result := in.fresh()
+
+	for _, arm := range expression.Arms {
+		in.openScope()
+		switch pattern := arm.Pattern.(type) {
+		case *ast.Identifier:
+			in.bind(pattern.Value, subject)
+		case *ast.LiteralPattern:
+			in.unify(subject, in.expression(pattern.Value), pattern.Token)
+		default:
+			in.pattern(pattern)
+		}
+		if arm.Guard != nil {
+			in.expression(arm.Guard)
+		}
+		in.unify(result, in.expression(arm.Body), tokenOf(arm.Body))
+		in.closeScope()
+	}
+
+	return result
+}
+
+func (in *inferrer) pattern(pattern ast.Pattern) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		in.bind(pattern.Value, in.fresh())
+	case *ast.ArrayPattern:
+		for _, element := range pattern.Elements {
+			in.pattern(element)
+		}
+	case *ast.HashPattern:
+		for _, pair := range pattern.Pairs {
+			in.pattern(pair.Value)
+		}
+	case *ast.RestPattern:
+		in.pattern(pattern.Name)
+	case *ast.DefaultPattern:
+		in.expression(pattern.Default)
+		in.pattern(pattern.Target)
+	}
+}
+
+func (in *inferrer) annotation(expression ast.TypeExpression) term {
+	switch expression := expression.(type) {
+	case *ast.NamedType:
+		if expression.Name == "any" {
+			return in.fresh()
+		}
+		if _, ok := basics[expression.Name]; ok {
+			return in.basic(expression.Name, expression.Token)
+		}
+		in.errorf(expression.Token, "unknown type %s", expression.Name)
+	case *ast.FunctionType:
+		function := &tcon{name: "fn", args: []term{}, required: len(expression.Parameters), origin: expression.Token}
+		for _, parameter := range expression.Parameters {
+			function.args = append(function.args, in.annotation(parameter))
+		}
+		function.args = append(function.args, in.annotation(expression.Return))
+		return function
+	}
+	return in.fresh()
+}
+
+func (in *inferrer) prune(t term) term {
+	for {
+		variable, ok := t.(*tvar)
+		if !ok {
+			return t
+		}
+		bound, ok := in.subst[variable.id]
+		if !ok {
+			return t
+		}
+		t = bound
+	}
+}
+
+func (in *inferrer) unify(a, b term, at token.Token) bool {
+	a, b = in.prune(a), in.prune(b)
+
+	if variable, ok := a.(*tvar); ok {
+		return in.bindVariable(variable, b, at)
+	}
+	if variable, ok := b.(*tvar); ok {
+		return in.bindVariable(variable, a, at)
+	}
+
+	ca, cb := a.(*tcon), b.(*tcon)
+	if ca.name != cb.name || len(ca.args) != len(cb.args) || ca.variadic != cb.variadic {
+		in.errorf(at, "type mismatch: %s (from %d:%d) conflicts with %s (from %d:%d)",
+			in.display(ca), ca.origin.Line, ca.origin.Column, in.display(cb), cb.origin.Line, cb.origin.Column)
+		return false
+	}
+
+	for i := range ca.args {
+		if !in.unify(ca.args[i], cb.args[i], at) {
+			return false
+		}
+	}
+	return true
+}
+
+func (in *inferrer) bindVariable(variable *tvar, t term, at token.Token) bool {
+	if other, ok := t.(*tvar); ok && other.id == variable.id {
+		return true
+	}
+
+	if in.occurs(variable.id, t) {
+		in.errorf(at, "infinite type: %s occurs in %s", in.display(variable), in.display(t))
+		return false
+	}
+
+	in.subst[variable.id] = t
+	return true
+}
+
+func (in *inferrer) occurs(id int, t term) bool {
+	switch t := in.prune(t).(type) {
+	case *tvar:
+		return t.id == id
+	case *tcon:
+		for _, arg := range t.args {
+			if in.occurs(id, arg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (in *inferrer) freeVariables(t term, free map[int]bool) {
+	switch t := in.prune(t).(type) {
+	case *tvar:
+		free[t.id] = true
+	case *tcon:
+		for _, arg := range t.args {
+			in.freeVariables(arg, free)
+		}
+	}
+}
+
+func (in *inferrer) generalize(t term) *scheme {
+	bound := map[int]bool{}
+	for current := in.env; current != nil; current = current.outer {
+		for _, s := range current.schemes {
+			free := map[int]bool{}
+			in.freeVariables(s.body, free)
+			for _, id := range s.vars {
+				delete(free, id)
+			}
+			for id := range free {
+				bound[id] = true
+			}
+		}
+	}
+
+	free := map[int]bool{}
+	in.freeVariables(t, free)
+
+	s := &scheme{body: t}
+	for id := range free {
+		if !bound[id] {
+			s.vars = append(s.vars, id)
+		}
+	}
+	sort.Ints(s.vars)
+	return s
+}
+
+func (in *inferrer) instantiate(s *scheme) term {
+	if len(s.vars) == 0 {
+		return s.body
+	}
+
+	mapping := map[int]term{}
+	for _, id := range s.vars {
+		mapping[id] = in.fresh()
+	}
+	return in.replace(s.body, mapping)
+}
+
+func (in *inferrer) replace(t term, mapping map[int]term) term {
+	switch t := in.prune(t).(type) {
+	case *tvar:
+		if replacement, ok := mapping[t.id]; ok {
+			return replacement
+		}
+		return t
+	case *tcon:
+		copied := *t
+		copied.args = []term{}
+		for _, arg := range t.args {
+			copied.args = append(copied.args, in.replace(arg, mapping))
+		}
+		return &copied
+	}
+	return t
+}
+
+func (in *inferrer) export(t term, names map[int]string) Type {
+	switch t := in.prune(t).(type) {
+	case *tvar:
+		if _, ok := names[t.id]; !ok {
+			names[t.id] = variableName(len(names))
+		}
+		return &Variable{Name: names[t.id]}
+	case *tcon:
+		if t.name != "fn" {
+			return basics[t.name]
+		}
+
+		function := &Function{Parameters: []Type{}, Required: t.required, Variadic: t.variadic}
+		for _, arg := range t.args[:len(t.args)-1] {
+			function.Parameters = append(function.Parameters, in.export(arg, names))
+		}
+		function.Return = in.export(t.args[len(t.args)-1], names)
+		return function
+	}
+	return Any
+}
+
+func (in *inferrer) display(t term) string {
+	return in.export(t, map[int]string{}).String()
+}
+
+func (in *inferrer) errorf(tok token.Token, format string, args ...interface{}) {
+	in.result.Diagnostics = append(in.result.Diagnostics, Diagnostic{
+		Line:    tok.Line,
+		Column:  tok.Column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func variableName(index int) string {
+	name := string(rune('a' + index%26))
+	if index >= 26 {
+		name += fmt.Sprint(index / 26)
+	}
+	return name
+}
+
+func letNames(statement *ast.LetStatement) []*ast.Identifier {
+	if statement.Pattern == nil {
+		return []*ast.Identifier{statement.Name}
+	}
+	return ast.BoundNames(statement.Pattern)
+}
+
+func endsWithValue(statement ast.Statement) bool {
+	switch statement.(type) {
+	case *ast.ExpressionStatement, *ast.ReturnStatement, *ast.ThrowStatement:
+		return true
+	}
+	return false
+}
+
+func typeToken(expression ast.TypeExpression) token.Token {
+	switch expression := expression.(type) {
+	case *ast.NamedType:
+		return expression.Token
+	case *ast.FunctionType:
+		return expression.Token
+	}
+	return token.Token{}
+}
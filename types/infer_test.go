@@ -0,0 +1,103 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInferBindings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"let x = 5;", []string{"x: int"}},
+		{"let x = !true;", []string{"x: bool"}},
+		{"let s = \"a\" + \"b\";", []string{"s: string"}},
+		{"let id = fn(x) { x };", []string{"id: fn('a) -> 'a"}},
+		{"let add = fn(a, b) { a + b * 2 };", []string{"add: fn(int, int) -> int"}},
+		{"let apply = fn(f, x) { f(x) };", []string{"apply: fn(fn('a) -> 'b, 'a) -> 'b"}},
+		{"let compose = fn(f, g) { fn(x) { f(g(x)) } };", []string{"compose: fn(fn('a) -> 'b, fn('c) -> 'a) -> fn('c) -> 'b"}},
+		{"let id = fn(x) { x }; let a = id(1); let b = id(true);", []string{"id: fn('a) -> 'a", "a: int", "b: bool"}},
+		{"let fact = fn(n) { if (n == 0) { 1 } else { n * fact(n - 1) } };", []string{"fact: fn(int) -> int"}},
+		{"let f = fn(x) { if (x) { return 1; } 2 };", []string{"f: fn('a) -> int"}},
+		{"let a = 1; let b = if (a) { 2 } else { 3 };", []string{"a: int", "b: int"}},
+		{"let c = 1 ? 2 : 3;", []string{"c: int"}},
+		{"let n = !\"s\";", []string{"n: bool"}},
+		{"let x = 0; while (1) { x = x + 1; }", []string{"x: int"}},
+		{"let m = match (1) { n if n => n, _ => 0 };", []string{"m: int"}},
+		{"let both = fn(a, b) { a && b };", []string{"both: fn('a, 'a) -> 'a"}},
+		{"let f = fn(a, b = 1, ...rest) { a - b };", []string{"f: fn(int, int, ...) -> int"}},
+		{"let f = fn(x: string) { x };", []string{"f: fn(string) -> string"}},
+		{"let f = fn(x) -> bool { x };", []string{"f: fn(bool) -> bool"}},
+		{"export let [a, {b: c}] = d;", []string{"a: 'a", "c: 'a"}},
+		{"let x = 1; if (true) { let y = 2; };", []string{"x: int"}},
+		{"let add = fn(x, y) { x + y };", []string{"add: fn(int, int) -> int"}},
+		{"let e = fn() { let z = 1; };", []string{"e: fn() -> null"}},
+		{"let e = fn() { return; };", []string{"e: fn() -> null"}},
+		{"let e = fn() {};", []string{"e: fn() -> null"}},
+	}
+
+	for _, tt := range tests {
+		program, _ := check(t, tt.input)
+		inference := Infer(program)
+
+		if len(inference.Diagnostics) != 0 {
+			t.Errorf("input %q: unexpected diagnostics: %v", tt.input, inference.Diagnostics)
+			continue
+		}
+
+		actual := []string{}
+		for _, binding := range inference.Bindings {
+			actual = append(actual, binding.Name+": "+binding.Type.String())
+		}
+
+		if strings.Join(actual, "\n") != strings.Join(tt.expected, "\n") {
+			t.Errorf("input %q: wrong bindings.\nexpected=%q\ngot=%q", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+func TestInferDiagnostics(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"1 + true;", []string{"1:3: type mismatch: int (from 1:1) conflicts with bool (from 1:5)"}},
+		{"let f = fn(x) { x + 1 };\nf(true);", []string{"2:3: type mismatch: int (from 1:21) conflicts with bool (from 2:3)"}},
+		{"1 && true;", []string{"1:3: type mismatch: int (from 1:1) conflicts with bool (from 1:6)"}},
+		{"if (true) { 2 } else { \"a\" };", []string{"1:1: type mismatch: int (from 1:13) conflicts with string (from 1:24)"}},
+		{"let f = fn(x) { x(x) };", []string{"1:18: infinite type: 'a occurs in fn('a) -> 'b"}},
+		{"let f = fn(a, b) { a }; f(1);", []string{"1:26: not enough arguments in call to f (have 1, want 2)"}},
+		{"let x = 5; x(1);", []string{"1:13: type mismatch: int (from 1:9) conflicts with fn(int) -> 'a (from 1:13)"}},
+		{"true + false;", []string{"1:6: invalid operation: (true + false) (operator + not defined on bool)"}},
+		{"let add = fn(x, y) { x + y };\nadd(true, false);", []string{
+			"2:5: type mismatch: int (from 1:24) conflicts with bool (from 2:5)",
+			"2:11: type mismatch: int (from 1:24) conflicts with bool (from 2:11)",
+		}},
+		{"let e = fn() { let z = 1; };\nlet g: int = e();", []string{"2:5: type mismatch: int (from 2:8) conflicts with null (from 1:9)"}},
+		{"let id = fn(x) { x }; id(1) + id(true);", []string{"1:29: type mismatch: int (from 1:26) conflicts with bool (from 1:34)"}},
+	}
+
+	for _, tt := range tests {
+		program, _ := check(t, tt.input)
+		inference := Infer(program)
+
+		actual := []string{}
+		for _, diagnostic := range inference.Diagnostics {
+			actual = append(actual, diagnostic.String())
+		}
+
+		if strings.Join(actual, "\n") != strings.Join(tt.expected, "\n") {
+			t.Errorf("input %q: wrong diagnostics.\nexpected=%q\ngot=%q", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+func TestLetPolymorphismIsLimitedToFunctions(t *testing.T) {
+	program, _ := check(t, "let f = fn(g) { let h = g; h(1) + h(true) };")
+	inference := Infer(program)
+
+	if len(inference.Diagnostics) != 1 || !strings.Contains(inference.Diagnostics[0].Message, "type mismatch") {
+		t.Errorf("expected a single type mismatch. got=%v", inference.Diagnostics)
+	}
+}
@@ -0,0 +1,118 @@
+package types
+
+import (
+	"bytes"
+	"strings"
+)
+
+type Type interface {
+	String() string
+}
+
+type Basic struct {
+	Name string
+}
+
+func (basic *Basic) String() string {
+	return basic.Name
+}
+
+var (
+	Int    = &Basic{Name: "int"}
+	String = &Basic{Name: "string"}
+	Bool   = &Basic{Name: "bool"}
+	Null   = &Basic{Name: "null"}
+	Any    = &Basic{Name: "any"}
+)
+
+var basics = map[string]Type{
+	"int":    Int,
+	"string": String,
+	"bool":   Bool,
+	"null":   Null,
+	"any":    Any,
+}
+
+type Function struct {
+	Parameters []Type
+	Required   int
+	Variadic   bool
+	Return     Type
+}
+
+func (function *Function) String() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for _, p := range function.Parameters {
+		params = append(params, p.String())
+	}
+	if function.Variadic {
+		params = append(params, "...")
+	}
+
+	out.WriteString("fn(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") -> ")
+	out.WriteString(function.Return.String())
+
+	return out.String()
+}
+
+func Identical(a, b Type) bool {
+	switch a := a.(type) {
+	case *Basic:
+		return a == b
+	case *Function:
+		b, ok := b.(*Function)
+		if !ok || len(a.Parameters) != len(b.Parameters) || a.Required != b.Required || a.Variadic != b.Variadic {
+			return false
+		}
+		for i := range a.Parameters {
+			if !Identical(a.Parameters[i], b.Parameters[i]) {
+				return false
+			}
+		}
+		return Identical(a.Return, b.Return)
+	}
+	return false
+}
+
+func AssignableTo(value, target Type) bool {
+	if value == Any || target == Any {
+		return true
+	}
+
+	valueFunction, ok := value.(*Function)
+	targetFunction, isFunction := target.(*Function)
+	if !ok || !isFunction {
+		return Identical(value, target)
+	}
+
+	if len(valueFunction.Parameters) != len(targetFunction.Parameters) || valueFunction.Variadic != targetFunction.Variadic {
+		return false
+	}
+	for i := range valueFunction.Parameters {
+		if !AssignableTo(targetFunction.Parameters[i], valueFunction.Parameters[i]) {
+			return false
+		}
+	}
+	return AssignableTo(valueFunction.Return, targetFunction.Return)
+}
+
+func known(typ Type) bool {
+	return typ != Any
+}
+
+func join(types []Type) Type {
+	if len(types) == 0 {
+		return Any
+	}
+
+	for _, typ := range types[1:] {
+		if !Identical(types[0], typ) {
+			return Any
+		}
+	}
+	return types[0]
+}